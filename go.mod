@@ -1 +1,3 @@
 module github.com/mithrandie/ternary
+
+go 1.19
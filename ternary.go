@@ -10,6 +10,9 @@
   TRUE:     1
 */
 //
+// A Value other than FALSE, UNKNOWN and TRUE is out of range, and the results of functions for such a value
+// are undefined. Use IsValid to check a value, or Normalize to bring it into range.
+//
 // Truth tables
 /*
   NOT(A) - Logical negation
@@ -54,6 +57,61 @@
   |    | T | F | U | T |
   +----+---+---+---+---+
 
+  NIMP(A, B) - Material nonimplication. AND(A, NOT(B))
+  +--------+-----------+
+  |        |     B     |
+  | A ↛ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | F | F |
+  | A  | U | U | U | F |
+  |    | T | T | U | F |
+  +----+---+---+---+---+
+
+  DIFF2(A, B) - Difference, "A but not B". Same as NIMP(A, B)
+  +--------+-----------+
+  |        |     B     |
+  | A − B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | F | F |
+  | A  | U | U | U | F |
+  |    | T | T | U | F |
+  +----+---+---+---+---+
+
+  CIMP(A, B) - Converse implication. IMP(B, A)
+  +--------+-----------+
+  |        |     B     |
+  | A ← B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | T | U | F |
+  | A  | U | T | U | U |
+  |    | T | T | T | T |
+  +----+---+---+---+---+
+
+  CNIMP(A, B) - Converse nonimplication. AND(NOT(A), B)
+  +--------+-----------+
+  |        |     B     |
+  | A ↚ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | U | T |
+  | A  | U | F | U | U |
+  |    | T | F | F | F |
+  +----+---+---+---+---+
+
+  GIMP(A, B) - Gödel implication. T if A ≤ B, otherwise B
+  +--------+-----------+
+  |        |     B     |
+  | A ⇒ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | T | T | T |
+  | A  | U | F | T | T |
+  |    | T | F | U | T |
+  +----+---+---+---+---+
+
   EQV(A, B) - Logical biconditional. OR(AND(A, B), AND(NOT(A), NOT(B)))
   +--------+-----------+
   |        |     B     |
@@ -65,16 +123,107 @@
   |    | T | F | U | T |
   +----+---+---+---+---+
 
+  XOR(A, B) - Logical exclusive disjunction. AND(OR(A, B), NOT(AND(A, B)))
+  +--------+-----------+
+  |        |     B     |
+  | A ⊕ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | U | T |
+  | A  | U | U | U | U |
+  |    | T | T | U | F |
+  +----+---+---+---+---+
+
+  STROKE(A, B) - Sheffer stroke. NOT(AND(A, B))
+  +--------+-----------+
+  |        |     B     |
+  | A ↑ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | T | T | T |
+  | A  | U | T | U | U |
+  |    | T | T | U | F |
+  +----+---+---+---+---+
+
+  DAGGER(A, B) - Peirce arrow. NOT(OR(A, B))
+  +--------+-----------+
+  |        |     B     |
+  | A ↓ B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | T | U | F |
+  | A  | U | U | U | F |
+  |    | T | F | F | F |
+  +----+---+---+---+---+
+
+  PAND(A, B) - Product conjunction. Product of A and B mapped to [0, 1], rounded to the nearest value
+  +--------+-----------+
+  |        |     B     |
+  | A · B  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | F | F |
+  | A  | U | F | F | U |
+  |    | T | F | U | T |
+  +----+---+---+---+---+
+
+  AGREE(A, B) - Agreement or unknown. A if B is UNKNOWN, B if A is UNKNOWN, UNKNOWN if A and B conflict
+  +--------+-----------+
+  |        |     B     |
+  | AGREE  |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | F | F | U |
+  | A  | U | F | U | T |
+  |    | T | U | T | T |
+  +----+---+---+---+---+
+
+  WEQV(A, B) - Weak biconditional. Same as EQV(A, B) except that WEQV(U, U) is T
+  +--------+-----------+
+  |        |     B     |
+  | WEQV   |---+---+---|
+  |        | F | U | T |
+  |----+---+---+---+---|
+  |    | F | T | U | F |
+  | A  | U | U | T | U |
+  |    | T | F | U | T |
+  +----+---+---+---+---+
+
 */
 package ternary
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"reflect"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
+// ConversionError is returned when a source cannot be converted to a ternary value.
+type ConversionError struct {
+	Source interface{}
+}
+
+// Error returns the error message including the source that failed to be converted.
+func (e *ConversionError) Error() string {
+	switch e.Source.(type) {
+	case string, byte, rune:
+		return fmt.Sprintf("convert from %q: invalid value", e.Source)
+	}
+	return fmt.Sprintf("convert from %v: invalid value", e.Source)
+}
+
 // Value represents a truth value
 type Value int8
 
@@ -90,14 +239,143 @@ var literals = map[Value]string{
 	TRUE:    "TRUE",
 }
 
+// IconNames maps each value to the icon identifier returned by IconName.
+// The mapping can be replaced to match an icon library.
+var IconNames = map[Value]string{
+	FALSE:   "x-circle",
+	UNKNOWN: "help-circle",
+	TRUE:    "check-circle",
+}
+
 // String returns string representation of the value.
 func (value Value) String() string {
 	return literals[value]
 }
 
+// Format implements fmt.Formatter.
+// The verbs %v and %s format the string representation, %q formats it as a quoted string,
+// and %x and %X format it in hexadecimal as for a string.
+// The verb %d formats the integer representation -1, 0 or 1, and %c formats the single character
+// 'F', 'U' or 'T'.
+// The verb %#v formats the value in Go syntax as GoString does.
+// Flags, width and precision are applied in the same way as for the standard types.
+func (value Value) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprintf(f, formatDirective(f, "-", 's'), value.GoString())
+			return
+		}
+		fmt.Fprintf(f, formatDirective(f, "+-# 0", verb), value.String())
+	case 's', 'q', 'x', 'X':
+		fmt.Fprintf(f, formatDirective(f, "+-# 0", verb), value.String())
+	case 'd':
+		fmt.Fprintf(f, formatDirective(f, "+-# 0", verb), int8(value))
+	case 'c':
+		fmt.Fprintf(f, formatDirective(f, "+-# 0", verb), rune(value.Byte()))
+	default:
+		fmt.Fprintf(f, "%%!%c(ternary.Value=%s)", verb, value.String())
+	}
+}
+
+func formatDirective(f fmt.State, flags string, verb rune) string {
+	var buf strings.Builder
+	buf.WriteByte('%')
+	for _, flag := range flags {
+		if f.Flag(int(flag)) {
+			buf.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		buf.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		buf.WriteByte('.')
+		buf.WriteString(strconv.Itoa(precision))
+	}
+	buf.WriteRune(verb)
+	return buf.String()
+}
+
+// GoString implements fmt.GoStringer.
+// Returns "ternary.FALSE", "ternary.UNKNOWN" or "ternary.TRUE", and returns a conversion such as
+// "ternary.Value(2)" if the value is out of range.
+func (value Value) GoString() string {
+	if !value.IsValid() {
+		return fmt.Sprintf("ternary.Value(%d)", int8(value))
+	}
+	return "ternary." + value.String()
+}
+
+// IsValid returns true if the value is any of FALSE, UNKNOWN and TRUE.
+func (value Value) IsValid() bool {
+	return FALSE <= value && value <= TRUE
+}
+
+// MarshalJSON returns the string representation of the value as a JSON string.
+func (value Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(value.String())
+}
+
+// UnmarshalJSON sets the value converted from a JSON string, number, boolean or null.
+// A string is converted by ConvertFromString and a number by ConvertFromFloat64,
+// so any JSON representation of -1, 0 or 1 such as 1.0 or -1e0 is accepted,
+// a boolean is converted by ConvertFromBool, and null is converted to UNKNOWN.
+func (value *Value) UnmarshalJSON(data []byte) error {
+	s := string(bytes.TrimSpace(data))
+
+	var v Value
+	var err error
+	switch {
+	case s == "null":
+		v = UNKNOWN
+	case s == "true":
+		v = TRUE
+	case s == "false":
+		v = FALSE
+	case strings.HasPrefix(s, "\""):
+		var str string
+		if err = json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		v, err = ConvertFromString(str)
+	default:
+		var n json.Number
+		if json.Unmarshal(data, &n) != nil {
+			return &ConversionError{Source: json.Number(s)}
+		}
+		f, ferr := n.Float64()
+		if ferr != nil {
+			return &ConversionError{Source: n}
+		}
+		v, err = ConvertFromFloat64(f)
+	}
+	if err != nil {
+		return err
+	}
+
+	*value = v
+	return nil
+}
+
+// MarshalText returns the string representation of the value.
+func (value Value) MarshalText() ([]byte, error) {
+	return []byte(value.String()), nil
+}
+
+// UnmarshalText sets the value converted from the text by ConvertFromString.
+func (value *Value) UnmarshalText(text []byte) error {
+	v, err := ConvertFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
 // Int returns integer representation of the value.
 func (value Value) Int() int64 {
-	return reflect.ValueOf(value).Int()
+	return int64(value)
 }
 
 // ParseBool returns true if the value is TRUE, otherwise returns false.
@@ -108,6 +386,142 @@ func (value Value) ParseBool() bool {
 	return true
 }
 
+// IconName returns the icon identifier of the value defined in IconNames.
+func (value Value) IconName() string {
+	return IconNames[value]
+}
+
+// StatusEmoji returns an emoji of the value followed by an arrow showing the trend from the previous value.
+// The value is shown as 🟢 for TRUE, 🟡 for UNKNOWN and 🔴 for FALSE, and the trend is shown as
+// ⬆️ if the value is greater than the previous value in the order FALSE < UNKNOWN < TRUE,
+// ⬇️ if it is less, and ➡️ if it is unchanged.
+func (value Value) StatusEmoji(previous Value) string {
+	var emoji string
+	switch value {
+	case TRUE:
+		emoji = "🟢"
+	case FALSE:
+		emoji = "🔴"
+	default:
+		emoji = "🟡"
+	}
+
+	switch {
+	case previous < value:
+		return emoji + "⬆️"
+	case value < previous:
+		return emoji + "⬇️"
+	}
+	return emoji + "➡️"
+}
+
+// GraphQLEnum returns the GraphQL enum name of the value, "FALSE", "UNKNOWN" or "TRUE".
+func (value Value) GraphQLEnum() string {
+	return value.String()
+}
+
+// Byte returns a single ASCII character representation of the value, 'F', 'U' or 'T'.
+func (value Value) Byte() byte {
+	switch value {
+	case FALSE:
+		return 'F'
+	case TRUE:
+		return 'T'
+	}
+	return 'U'
+}
+
+// Symbol returns a single character string representation of the value, "F", "U" or "T".
+func (value Value) Symbol() string {
+	return string(value.Byte())
+}
+
+// Rune returns a single character representation of the value, 'F', 'U' or 'T'.
+func (value Value) Rune() rune {
+	return rune(value.Byte())
+}
+
+// DualRail returns the dual-rail encoding of the value.
+// TRUE asserts only the t rail, FALSE asserts only the f rail, and UNKNOWN asserts neither.
+func (value Value) DualRail() (t bool, f bool) {
+	return value == TRUE, value == FALSE
+}
+
+// WrapperBool returns the value as an optional boolean in the manner of the protobuf BoolValue wrapper.
+// It is the same as BoolPtr.
+func (value Value) WrapperBool() *bool {
+	return value.BoolPtr()
+}
+
+// BoolPtr returns nil if the value is UNKNOWN, otherwise returns a pointer to the boolean of the value.
+func (value Value) BoolPtr() *bool {
+	if value == UNKNOWN {
+		return nil
+	}
+	b := value == TRUE
+	return &b
+}
+
+// NullBool returns the value as a sql.NullBool.
+// Returns an invalid NullBool, representing NULL, if the value is UNKNOWN.
+func (value Value) NullBool() sql.NullBool {
+	if value == UNKNOWN {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: value == TRUE, Valid: true}
+}
+
+// DesignationPolicy represents which truth values are treated as designated.
+type DesignationPolicy int
+
+const (
+	// DesignateTrue designates only TRUE, as in Kleene's strong logic K3.
+	DesignateTrue DesignationPolicy = iota
+	// DesignateTrueUnknown designates both TRUE and UNKNOWN, as in Priest's logic of paradox LP.
+	DesignateTrueUnknown
+)
+
+// IsDesignated returns true if the value is designated under the policy.
+func (value Value) IsDesignated(d DesignationPolicy) bool {
+	switch d {
+	case DesignateTrue:
+		return value == TRUE
+	case DesignateTrueUnknown:
+		return value != FALSE
+	}
+	return false
+}
+
+// Update returns the value revised by the evidence.
+// Agreeing or UNKNOWN evidence leaves the value unchanged, conflicting evidence weakens it to UNKNOWN,
+// and an UNKNOWN value adopts the evidence. This is the same as AgreeOrUnknown(value, evidence).
+func (value Value) Update(evidence Value) Value {
+	return AgreeOrUnknown(value, evidence)
+}
+
+// Matches returns true if the two values are the same, or if either of them is UNKNOWN.
+func (value Value) Matches(other Value) bool {
+	return value == other || value == UNKNOWN || other == UNKNOWN
+}
+
+// Next returns the value one step above the value in the order FALSE < UNKNOWN < TRUE.
+// TRUE.Next() returns TRUE.
+func (value Value) Next() Value {
+	if value < TRUE {
+		return value + 1
+	}
+	return TRUE
+}
+
+// Prev returns the value one step below the value in the order FALSE < UNKNOWN < TRUE.
+// FALSE.Prev() returns FALSE.
+func (value Value) Prev() Value {
+	if FALSE < value {
+		return value - 1
+	}
+	return FALSE
+}
+
 // ConvertFromString converts a string to a ternary value.
 // If the string is any of "false", "FALSE" and "-1", then it is converted to FALSE.
 // If the string is any of "unknown", "UNKNOWN" and "0", then it is converted to UNKNOWN.
@@ -122,7 +536,29 @@ func ConvertFromString(s string) (Value, error) {
 	case literals[UNKNOWN], "0":
 		return UNKNOWN, nil
 	}
-	return UNKNOWN, errors.New(fmt.Sprintf("convert from %q: invalid value", s))
+	return UNKNOWN, &ConversionError{Source: s}
+}
+
+// ParseLoose converts a string to a ternary value, accepting more literals than ConvertFromString.
+// Surrounding white spaces are trimmed, and the literals are case-insensitive.
+// In addition to the strings accepted by ConvertFromString,
+// "yes", "on", "t" and "y" are converted to TRUE,
+// "no", "off", "f" and "n" are converted to FALSE,
+// and "maybe", "null", "nil", "na" and "?" are converted to UNKNOWN.
+// Otherwise, returns an error.
+func ParseLoose(s string) (Value, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "on", "t", "y":
+		return TRUE, nil
+	case "no", "off", "f", "n":
+		return FALSE, nil
+	case "maybe", "null", "nil", "na", "?":
+		return UNKNOWN, nil
+	}
+	if v, err := ConvertFromString(strings.TrimSpace(s)); err == nil {
+		return v, nil
+	}
+	return UNKNOWN, &ConversionError{Source: s}
 }
 
 // ConvertFromInt64 converts an integer to a ternary value.
@@ -137,7 +573,34 @@ func ConvertFromInt64(i int64) (Value, error) {
 	case 1:
 		return TRUE, nil
 	}
-	return UNKNOWN, errors.New(fmt.Sprintf("convert from %d: invalid value", i))
+	return UNKNOWN, &ConversionError{Source: i}
+}
+
+// ConvertFromFloat64 converts a floating-point number to a ternary value.
+// Returns FALSE if the number is -1, returns UNKNOWN if it is 0, and returns TRUE if it is 1.
+// Otherwise, including NaN, returns an error.
+func ConvertFromFloat64(f float64) (Value, error) {
+	switch f {
+	case -1:
+		return FALSE, nil
+	case 0:
+		return UNKNOWN, nil
+	case 1:
+		return TRUE, nil
+	}
+	return UNKNOWN, &ConversionError{Source: f}
+}
+
+// ClampFromInt64 converts an integer to a ternary value by its sign.
+// Returns FALSE if the integer is negative, returns UNKNOWN if it is 0, and returns TRUE if it is positive.
+func ClampFromInt64(i int64) Value {
+	switch {
+	case i < 0:
+		return FALSE
+	case 0 < i:
+		return TRUE
+	}
+	return UNKNOWN
 }
 
 // ConvertFromBool converts a boolean to a ternary value.
@@ -149,11 +612,138 @@ func ConvertFromBool(b bool) Value {
 	return FALSE
 }
 
+// ConvertFromGraphQLEnum converts a GraphQL enum name to a ternary value.
+// Accepts "FALSE", "UNKNOWN" and "TRUE", and also "MAYBE" as an alias of "UNKNOWN".
+// Enum names are case-sensitive. Otherwise, returns an error.
+func ConvertFromGraphQLEnum(s string) (Value, error) {
+	switch s {
+	case literals[FALSE]:
+		return FALSE, nil
+	case literals[UNKNOWN], "MAYBE":
+		return UNKNOWN, nil
+	case literals[TRUE]:
+		return TRUE, nil
+	}
+	return UNKNOWN, &ConversionError{Source: s}
+}
+
+// ConvertFromASCII converts a single ASCII character to a ternary value.
+// Accepts 'F', 'U' and 'T' in either upper or lower case. Otherwise, returns an error.
+func ConvertFromASCII(b byte) (Value, error) {
+	switch b {
+	case 'F', 'f':
+		return FALSE, nil
+	case 'U', 'u':
+		return UNKNOWN, nil
+	case 'T', 't':
+		return TRUE, nil
+	}
+	return UNKNOWN, &ConversionError{Source: b}
+}
+
+// ConvertFromRune converts a single character to a ternary value.
+// The characters 'F', 'U' and 'T' are accepted in either case, so the value returned by Rune
+// converts back to the same value.
+func ConvertFromRune(r rune) (Value, error) {
+	if 0 <= r && r < utf8.RuneSelf {
+		if v, err := ConvertFromASCII(byte(r)); err == nil {
+			return v, nil
+		}
+	}
+	return UNKNOWN, &ConversionError{Source: r}
+}
+
+// ConvertFromWrapperBool converts an optional boolean in the manner of the protobuf BoolValue wrapper
+// to a ternary value.
+// It is the same as ConvertFromBoolPtr.
+func ConvertFromWrapperBool(b *bool) Value {
+	return ConvertFromBoolPtr(b)
+}
+
+// ConvertFromBoolPtr converts a pointer to a boolean to a ternary value.
+// Returns UNKNOWN if the pointer is nil, otherwise converts the boolean by ConvertFromBool.
+func ConvertFromBoolPtr(b *bool) Value {
+	if b == nil {
+		return UNKNOWN
+	}
+	return ConvertFromBool(*b)
+}
+
+// ConvertFromNullBool converts a sql.NullBool to a ternary value.
+// Returns UNKNOWN if the NullBool is NULL, otherwise converts the boolean by ConvertFromBool.
+func ConvertFromNullBool(nb sql.NullBool) Value {
+	if !nb.Valid {
+		return UNKNOWN
+	}
+	return ConvertFromBool(nb.Bool)
+}
+
+type dualRail struct {
+	t bool
+	f bool
+}
+
+func (d dualRail) String() string {
+	return fmt.Sprintf("dual rail (%t, %t)", d.t, d.f)
+}
+
+// ConvertFromDualRail converts a dual-rail encoded pair to a ternary value.
+// Returns TRUE if only t is asserted, returns FALSE if only f is asserted, and returns UNKNOWN if neither is
+// asserted. Returns an error if both rails are asserted, since that state is illegal.
+func ConvertFromDualRail(t bool, f bool) (Value, error) {
+	switch {
+	case t && f:
+		return UNKNOWN, &ConversionError{Source: dualRail{t: t, f: f}}
+	case t:
+		return TRUE, nil
+	case f:
+		return FALSE, nil
+	}
+	return UNKNOWN, nil
+}
+
+// Normalize returns the valid value that has the same sign as the value.
+// Negative values are clamped to FALSE, and positive values are clamped to TRUE.
+func Normalize(value Value) Value {
+	switch {
+	case value < FALSE:
+		return FALSE
+	case TRUE < value:
+		return TRUE
+	}
+	return value
+}
+
 // Equal checks if two values are the same value, not logical equality.
 func Equal(a Value, b Value) Value {
 	return ConvertFromBool(a == b)
 }
 
+// Compare returns the result of comparing two values in the order FALSE < UNKNOWN < TRUE.
+// Returns FALSE if a is less than b, returns UNKNOWN if they are equal, and returns TRUE if a is greater than b.
+//
+// This is a structural comparison of the numeric representations, not a logical operation.
+// Use Eqv for logical biconditional.
+func Compare(a Value, b Value) Value {
+	switch {
+	case a < b:
+		return FALSE
+	case b < a:
+		return TRUE
+	}
+	return UNKNOWN
+}
+
+// Less returns true if a is less than b in the order FALSE < UNKNOWN < TRUE.
+func Less(a Value, b Value) bool {
+	return a < b
+}
+
+// Greater returns true if a is greater than b in the order FALSE < UNKNOWN < TRUE.
+func Greater(a Value, b Value) bool {
+	return b < a
+}
+
 // Not returns the result of logical negation for a value.
 func Not(a Value) Value {
 	return a * -1
@@ -180,24 +770,119 @@ func Imp(a Value, b Value) Value {
 	return Or(Not(a), b)
 }
 
+// Nimp returns the result of material nonimplication that is represented as "a does not imply b".
+func Nimp(a Value, b Value) Value {
+	return And(a, Not(b))
+}
+
+// Diff2 returns the difference that is represented as "a but not b".
+// It is the same operation as Nimp.
+func Diff2(a Value, b Value) Value {
+	return Nimp(a, b)
+}
+
+// Cimp returns the result of converse implication that is represented as "a is implied by b".
+func Cimp(a Value, b Value) Value {
+	return Imp(b, a)
+}
+
+// Cnimp returns the result of converse nonimplication that is represented as "a is not implied by b".
+func Cnimp(a Value, b Value) Value {
+	return And(Not(a), b)
+}
+
+// GodelImp returns the result of Gödel implication that is represented as "a implies b".
+// Returns TRUE if a is less than or equal to b in the order FALSE < UNKNOWN < TRUE, otherwise returns b.
+// Unlike Imp, GodelImp(UNKNOWN, UNKNOWN) is TRUE and GodelImp(UNKNOWN, FALSE) is FALSE.
+func GodelImp(a Value, b Value) Value {
+	if a <= b {
+		return TRUE
+	}
+	return b
+}
+
 // Eqv returns the result of logical biconditional for two values.
 func Eqv(a Value, b Value) Value {
 	return a * b
 }
 
-// All returns the result of logical conjunction on all values.
-func All(values []Value) Value {
-	t := TRUE
-	for i := 0; i < len(values); i++ {
-		t = And(t, values[i])
-		if t == FALSE {
-			return FALSE
-		}
-	}
-	return t
+// Xor returns the result of exclusive disjunction for two values.
+func Xor(a Value, b Value) Value {
+	return And(Or(a, b), Not(And(a, b)))
 }
 
-// Any returns the result of logical disjunction on all values.
+// Stroke returns the result of the Sheffer stroke, or logical NAND, for two values.
+//
+// Stroke alone is functionally complete for the restriction to FALSE and TRUE, as in classical logic.
+// It is not functionally complete for three values, since no combination of Kleene operators can turn
+// UNKNOWN operands into a result other than UNKNOWN.
+func Stroke(a Value, b Value) Value {
+	return Not(And(a, b))
+}
+
+// Dagger returns the result of the Peirce arrow, or logical NOR, for two values.
+//
+// As with Stroke, Dagger alone is functionally complete only for the restriction to FALSE and TRUE.
+func Dagger(a Value, b Value) Value {
+	return Not(Or(a, b))
+}
+
+// ProductAnd returns the result of conjunction by the product t-norm for two values.
+// FALSE, UNKNOWN and TRUE are mapped to 0, 0.5 and 1, and the product is rounded to the nearest of them,
+// with halfway cases rounded down. So products up to 0.25 are FALSE, products up to 0.75 are UNKNOWN,
+// and greater products are TRUE, which makes ProductAnd(UNKNOWN, UNKNOWN) FALSE.
+func ProductAnd(a Value, b Value) Value {
+	p := (float64(a) + 1) / 2 * (float64(b) + 1) / 2
+	switch {
+	case p <= 0.25:
+		return FALSE
+	case p <= 0.75:
+		return UNKNOWN
+	}
+	return TRUE
+}
+
+// WeakEqv returns the result of weak biconditional for two values.
+// It is the same as Eqv except that two UNKNOWN values are regarded as equivalent.
+func WeakEqv(a Value, b Value) Value {
+	if a == UNKNOWN && b == UNKNOWN {
+		return TRUE
+	}
+	return Eqv(a, b)
+}
+
+// AgreeOrUnknown returns the value on which two values agree.
+// If either value is UNKNOWN, the other value is returned. If the values are FALSE and TRUE, returns UNKNOWN.
+func AgreeOrUnknown(a Value, b Value) Value {
+	switch {
+	case a == UNKNOWN:
+		return b
+	case b == UNKNOWN, a == b:
+		return a
+	}
+	return UNKNOWN
+}
+
+// Majority returns the median of three values.
+// Returns TRUE if at least two of them are TRUE, returns FALSE if at least two of them are FALSE,
+// and otherwise returns UNKNOWN.
+func Majority(a Value, b Value, c Value) Value {
+	return Or(Or(And(a, b), And(b, c)), And(a, c))
+}
+
+// All returns the result of logical conjunction on all values.
+func All(values []Value) Value {
+	t := TRUE
+	for i := 0; i < len(values); i++ {
+		t = And(t, values[i])
+		if t == FALSE {
+			return FALSE
+		}
+	}
+	return t
+}
+
+// Any returns the result of logical disjunction on all values.
 func Any(values []Value) Value {
 	t := FALSE
 	for i := 0; i < len(values); i++ {
@@ -208,3 +893,1089 @@ func Any(values []Value) Value {
 	}
 	return t
 }
+
+// AndAll returns the result of logical conjunction on all arguments in the same way as All.
+func AndAll(values ...Value) Value {
+	return All(values)
+}
+
+// OrAny returns the result of logical disjunction on all arguments in the same way as Any.
+func OrAny(values ...Value) Value {
+	return Any(values)
+}
+
+// FirstFalse returns FALSE as soon as a FALSE value is found scanning from left to right.
+// Otherwise, returns UNKNOWN if any value is UNKNOWN, and returns TRUE if not.
+func FirstFalse(values []Value) Value {
+	v, _ := FirstFalseAt(values)
+	return v
+}
+
+// FirstFalseAt returns the same result as FirstFalse and the index of the first FALSE value.
+// The index is -1 if the scan reached the end of the values without finding FALSE.
+func FirstFalseAt(values []Value) (Value, int) {
+	t := TRUE
+	for i := 0; i < len(values); i++ {
+		if values[i] == FALSE {
+			return FALSE, i
+		}
+		t = And(t, values[i])
+	}
+	return t, -1
+}
+
+// IsValidArgument returns true if the conclusion is designated whenever all the premises are designated
+// under the policy.
+// The check is made for the given values only, not for every possible assignment.
+func IsValidArgument(premises []Value, conclusion Value, d DesignationPolicy) bool {
+	for i := 0; i < len(premises); i++ {
+		if !premises[i].IsDesignated(d) {
+			return true
+		}
+	}
+	return conclusion.IsDesignated(d)
+}
+
+// Zip returns the results of applying the operator to each pair of elements at the same position in two slices.
+// Returns an error if the slices have different lengths.
+func Zip(a []Value, b []Value, op func(x Value, y Value) Value) ([]Value, error) {
+	if len(a) != len(b) {
+		return nil, errors.New(fmt.Sprintf("zip %d and %d values: length mismatch", len(a), len(b)))
+	}
+	result := make([]Value, len(a))
+	for i := 0; i < len(a); i++ {
+		result[i] = op(a[i], b[i])
+	}
+	return result, nil
+}
+
+// TrueFraction returns the fraction of TRUE values among the values that are not UNKNOWN.
+// Returns NaN if there are no such values.
+func TrueFraction(values []Value) float64 {
+	trues := 0
+	definites := 0
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case TRUE:
+			trues++
+			definites++
+		case FALSE:
+			definites++
+		}
+	}
+	if definites < 1 {
+		return math.NaN()
+	}
+	return float64(trues) / float64(definites)
+}
+
+// FromQueryPresence converts a query parameter to a ternary value, treating the presence of a key as true.
+// Returns UNKNOWN if the key is not present, and returns TRUE if it is present with an empty value.
+// Otherwise, the value is converted by ParseLoose, and UNKNOWN is returned if the conversion fails.
+func FromQueryPresence(present bool, rawValue string) Value {
+	if !present {
+		return UNKNOWN
+	}
+	if len(rawValue) < 1 {
+		return TRUE
+	}
+	v, err := ParseLoose(rawValue)
+	if err != nil {
+		return UNKNOWN
+	}
+	return v
+}
+
+// Settle applies the step function repeatedly, starting from the initial value, until the value stops changing.
+// Returns the fixed point and true if it is reached within maxIter applications,
+// otherwise returns the last value and false.
+func Settle(initial Value, step func(Value) Value, maxIter int) (Value, bool) {
+	v := initial
+	for i := 0; i < maxIter; i++ {
+		next := step(v)
+		if next == v {
+			return v, true
+		}
+		v = next
+	}
+	return v, false
+}
+
+// FromCounts derives a ternary value from the numbers of true and false observations.
+// Returns TRUE if only trueCount reaches the threshold, returns FALSE if only falseCount reaches it,
+// and returns UNKNOWN if neither or both of them reach it.
+func FromCounts(trueCount int, falseCount int, threshold int) Value {
+	t := threshold <= trueCount
+	f := threshold <= falseCount
+	if t && !f {
+		return TRUE
+	}
+	if f && !t {
+		return FALSE
+	}
+	return UNKNOWN
+}
+
+// FromExitCode converts a process exit code to a ternary value.
+// Returns TRUE if the code is 0, returns UNKNOWN if it equals unknownCode, and otherwise returns FALSE.
+func FromExitCode(code int, unknownCode int) Value {
+	switch code {
+	case 0:
+		return TRUE
+	case unknownCode:
+		return UNKNOWN
+	}
+	return FALSE
+}
+
+// ConsistentAggregation returns true if All and Any return the same results as folding And and Or
+// over the values from left to right.
+func ConsistentAggregation(values []Value) bool {
+	and := TRUE
+	or := FALSE
+	for i := 0; i < len(values); i++ {
+		and = And(and, values[i])
+		or = Or(or, values[i])
+	}
+	return All(values) == and && Any(values) == or
+}
+
+// PartitionBy splits the items into three slices by the result of the predicate.
+func PartitionBy[T any](items []T, pred func(T) Value) (trues []T, unknowns []T, falses []T) {
+	for _, item := range items {
+		switch pred(item) {
+		case TRUE:
+			trues = append(trues, item)
+		case FALSE:
+			falses = append(falses, item)
+		default:
+			unknowns = append(unknowns, item)
+		}
+	}
+	return trues, unknowns, falses
+}
+
+// RoundingPolicy represents how a continuous truth degree is rounded to a ternary value.
+type RoundingPolicy int
+
+const (
+	// RoundTowardUnknown rounds any degree that is not exactly FALSE or TRUE to UNKNOWN.
+	RoundTowardUnknown RoundingPolicy = iota
+	// RoundTowardDefinite rounds any degree that is not exactly UNKNOWN to FALSE or TRUE.
+	RoundTowardDefinite
+	// RoundNearest rounds a degree to the nearest value. Halfway cases are rounded to UNKNOWN.
+	RoundNearest
+)
+
+// ConvertFromProbabilityWith converts a probability in the range [0, 1] to a ternary value using the
+// rounding policy.
+// The probability is mapped linearly onto the numeric representation, so 0 corresponds to FALSE,
+// 0.5 to UNKNOWN and 1 to TRUE.
+// Returns an error if the probability is out of range or NaN, or if the policy is not defined.
+func ConvertFromProbabilityWith(p float64, policy RoundingPolicy) (Value, error) {
+	if math.IsNaN(p) || p < 0 || 1 < p {
+		return UNKNOWN, &ConversionError{Source: p}
+	}
+
+	d := 2*p - 1
+	switch policy {
+	case RoundTowardUnknown:
+		switch d {
+		case -1:
+			return FALSE, nil
+		case 1:
+			return TRUE, nil
+		}
+		return UNKNOWN, nil
+	case RoundTowardDefinite:
+		switch {
+		case d < 0:
+			return FALSE, nil
+		case 0 < d:
+			return TRUE, nil
+		}
+		return UNKNOWN, nil
+	case RoundNearest:
+		switch {
+		case d < -0.5:
+			return FALSE, nil
+		case 0.5 < d:
+			return TRUE, nil
+		}
+		return UNKNOWN, nil
+	}
+	return UNKNOWN, errors.New(fmt.Sprintf("round with policy %d: invalid policy", policy))
+}
+
+// PreservesTrue returns true if the operator returns TRUE when both operands are TRUE.
+func PreservesTrue(op func(a Value, b Value) Value) bool {
+	return op(TRUE, TRUE) == TRUE
+}
+
+// PreservesFalse returns true if the operator returns FALSE when both operands are FALSE.
+func PreservesFalse(op func(a Value, b Value) Value) bool {
+	return op(FALSE, FALSE) == FALSE
+}
+
+// Get returns the value bound to the name.
+// A name that is not bound, or any name in a nil map, reads as UNKNOWN because UNKNOWN is the zero value,
+// so Get is the same as indexing the map and only exists to make that explicit.
+func Get(vars map[string]Value, name string) Value {
+	return vars[name]
+}
+
+// Substitute evaluates the template with the variable bindings.
+// Variables missing from the bindings read as UNKNOWN whether the template indexes the map directly or
+// looks them up with Get, since UNKNOWN is the zero value.
+func Substitute(template func(vars map[string]Value) Value, vars map[string]Value) Value {
+	return template(vars)
+}
+
+// TableDiff returns descriptions of the cells that differ between two truth tables.
+// The cell at [i][j] holds the result for the operands Value(i-1) and Value(j-1),
+// and each description reports the cell of a as got and the cell of b as want.
+func TableDiff(a [3][3]Value, b [3][3]Value) []string {
+	var diff []string
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if a[i][j] != b[i][j] {
+				diff = append(diff, fmt.Sprintf("at (%s, %s): got %s want %s", Value(i-1), Value(j-1), a[i][j], b[i][j]))
+			}
+		}
+	}
+	return diff
+}
+
+// DecideWithQuorum returns the majority of the values that are not UNKNOWN.
+// Returns UNKNOWN if fewer than minDefinite values are not UNKNOWN, or if TRUE and FALSE are tied.
+func DecideWithQuorum(values []Value, minDefinite int) Value {
+	trues := 0
+	falses := 0
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case TRUE:
+			trues++
+		case FALSE:
+			falses++
+		}
+	}
+	if trues+falses < minDefinite {
+		return UNKNOWN
+	}
+	switch {
+	case falses < trues:
+		return TRUE
+	case trues < falses:
+		return FALSE
+	}
+	return UNKNOWN
+}
+
+// FromBoolErr converts the results of a function returning a boolean and an error to a ternary value.
+// Returns UNKNOWN if the error is not nil, otherwise converts the boolean by ConvertFromBool.
+func FromBoolErr(b bool, err error) Value {
+	if err != nil {
+		return UNKNOWN
+	}
+	return ConvertFromBool(b)
+}
+
+// Run represents a value repeated Count times in a row.
+type Run struct {
+	V     Value
+	Count int
+}
+
+// RunLengthEncode returns runs of consecutive equal values.
+func RunLengthEncode(values []Value) []Run {
+	var runs []Run
+	for i := 0; i < len(values); i++ {
+		if 0 < len(runs) && runs[len(runs)-1].V == values[i] {
+			runs[len(runs)-1].Count++
+			continue
+		}
+		runs = append(runs, Run{V: values[i], Count: 1})
+	}
+	return runs
+}
+
+// RunLengthDecode expands runs into the sequence of values they represent.
+// Returns an error if any run has a negative count.
+func RunLengthDecode(runs []Run) ([]Value, error) {
+	n := 0
+	for _, run := range runs {
+		if run.Count < 0 {
+			return nil, errors.New(fmt.Sprintf("decode run of %s with count %d: invalid value", run.V, run.Count))
+		}
+		n += run.Count
+	}
+	values := make([]Value, 0, n)
+	for _, run := range runs {
+		for i := 0; i < run.Count; i++ {
+			values = append(values, run.V)
+		}
+	}
+	return values, nil
+}
+
+// AgreementScore returns the fraction of positions at which two slices hold the same value.
+// Returns NaN if the slices are empty, and returns an error if the slices have different lengths.
+func AgreementScore(a []Value, b []Value) (float64, error) {
+	if len(a) != len(b) {
+		return 0, errors.New(fmt.Sprintf("compare %d and %d values: length mismatch", len(a), len(b)))
+	}
+	if len(a) < 1 {
+		return math.NaN(), nil
+	}
+	agreements := 0
+	for i := 0; i < len(a); i++ {
+		if a[i] == b[i] {
+			agreements++
+		}
+	}
+	return float64(agreements) / float64(len(a)), nil
+}
+
+// CohenKappa returns Cohen's kappa coefficient, the agreement between two slices corrected for the agreement
+// expected by chance from the frequency of each value.
+// Returns NaN if the expected agreement is already perfect, as when both slices consist of one and the same
+// value, since kappa is undefined in that case. Returns NaN also if the slices are empty.
+// Returns an error if the slices have different lengths.
+func CohenKappa(a []Value, b []Value) (float64, error) {
+	if len(a) != len(b) {
+		return 0, errors.New(fmt.Sprintf("compare %d and %d values: length mismatch", len(a), len(b)))
+	}
+
+	n := len(a)
+	agreements := 0
+	var countsA [3]int
+	var countsB [3]int
+	for i := 0; i < n; i++ {
+		if a[i] == b[i] {
+			agreements++
+		}
+		countsA[a[i]+1]++
+		countsB[b[i]+1]++
+	}
+
+	chance := 0
+	for i := 0; i < 3; i++ {
+		chance += countsA[i] * countsB[i]
+	}
+	if chance == n*n {
+		return math.NaN(), nil
+	}
+	return float64(n*agreements-chance) / float64(n*n-chance), nil
+}
+
+// TransitionMatrix counts how often each value is immediately followed by each value in the sequence.
+// The count at [i][j] is the number of times Value(i-1) is followed by Value(j-1).
+func TransitionMatrix(sequence []Value) [3][3]int {
+	var m [3][3]int
+	for i := 1; i < len(sequence); i++ {
+		m[sequence[i-1]+1][sequence[i]+1]++
+	}
+	return m
+}
+
+// MedianFilter3 returns the values smoothed by a sliding median of three, ordered as FALSE < UNKNOWN < TRUE.
+// Each value is replaced by the median of itself and its two neighbors.
+// The first and last values have only one neighbor, so they are kept as they are.
+func MedianFilter3(values []Value) []Value {
+	result := make([]Value, len(values))
+	copy(result, values)
+	for i := 1; i < len(values)-1; i++ {
+		result[i] = Majority(values[i-1], values[i], values[i+1])
+	}
+	return result
+}
+
+// KeyValue represents a value with a key that it is grouped by.
+type KeyValue struct {
+	Key string
+	V   Value
+}
+
+// GroupAll groups the values by their keys and returns the result of All for each group.
+func GroupAll(pairs []KeyValue) map[string]Value {
+	groups := groupValues(pairs)
+	result := make(map[string]Value, len(groups))
+	for key, values := range groups {
+		result[key] = All(values)
+	}
+	return result
+}
+
+// GroupAny groups the values by their keys and returns the result of Any for each group.
+func GroupAny(pairs []KeyValue) map[string]Value {
+	groups := groupValues(pairs)
+	result := make(map[string]Value, len(groups))
+	for key, values := range groups {
+		result[key] = Any(values)
+	}
+	return result
+}
+
+func groupValues(pairs []KeyValue) map[string][]Value {
+	groups := make(map[string][]Value)
+	for _, pair := range pairs {
+		groups[pair.Key] = append(groups[pair.Key], pair.V)
+	}
+	return groups
+}
+
+// Atomic is a ternary value that can be loaded and stored atomically.
+// The zero value holds UNKNOWN.
+type Atomic struct {
+	v atomic.Int32
+}
+
+// Load atomically loads the value.
+func (a *Atomic) Load() Value {
+	return Value(a.v.Load())
+}
+
+// Store atomically stores the value.
+func (a *Atomic) Store(value Value) {
+	a.v.Store(int32(value))
+}
+
+// CompareAndSwap stores the new value if the current value is old, and reports whether the swap occurred.
+func (a *Atomic) CompareAndSwap(old Value, new Value) bool {
+	return a.v.CompareAndSwap(int32(old), int32(new))
+}
+
+// AndExplain returns the result of logical conjunction on all values and the index of the value that
+// determined it.
+// The index is that of the first FALSE value, or of the first UNKNOWN value if there is no FALSE value.
+// If all values are TRUE, the index is -1.
+func AndExplain(values []Value) (Value, int) {
+	unknown := -1
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case FALSE:
+			return FALSE, i
+		case UNKNOWN:
+			if unknown < 0 {
+				unknown = i
+			}
+		}
+	}
+	if -1 < unknown {
+		return UNKNOWN, unknown
+	}
+	return TRUE, -1
+}
+
+// FromInterval converts a confidence interval in the range [-1, 1] to a ternary value.
+// Returns TRUE if the whole interval is above zero, returns FALSE if it is below zero,
+// and returns UNKNOWN if it contains zero.
+// Returns an error if lower is greater than upper, or if either bound is out of range.
+func FromInterval(lower float64, upper float64) (Value, error) {
+	if !(-1 <= lower && lower <= upper && upper <= 1) {
+		return UNKNOWN, errors.New(fmt.Sprintf("convert from [%v, %v]: invalid interval", lower, upper))
+	}
+	switch {
+	case 0 < lower:
+		return TRUE, nil
+	case upper < 0:
+		return FALSE, nil
+	}
+	return UNKNOWN, nil
+}
+
+// MergeAll returns the value on which all the values that are not UNKNOWN agree.
+// Returns UNKNOWN if they conflict, or if there are no such values.
+func MergeAll(values []Value) Value {
+	t := UNKNOWN
+	for i := 0; i < len(values); i++ {
+		if values[i] == UNKNOWN {
+			continue
+		}
+		if t == UNKNOWN {
+			t = values[i]
+		} else if t != values[i] {
+			return UNKNOWN
+		}
+	}
+	return t
+}
+
+// MaxAssignmentVariables is the maximum number of variables Assignments accepts.
+const MaxAssignmentVariables = 12
+
+// Assignments returns all 3^n combinations of values for n variables.
+// The combinations are in lexicographic order by FALSE < UNKNOWN < TRUE, so the last variable changes fastest.
+// Returns nil if n is negative or greater than MaxAssignmentVariables.
+func Assignments(n int) [][]Value {
+	if n < 0 || MaxAssignmentVariables < n {
+		return nil
+	}
+
+	total := 1
+	for i := 0; i < n; i++ {
+		total *= 3
+	}
+
+	assignments := make([][]Value, total)
+	for i := 0; i < total; i++ {
+		assignment := make([]Value, n)
+		k := i
+		for j := n - 1; 0 <= j; j-- {
+			assignment[j] = Value(k%3 - 1)
+			k /= 3
+		}
+		assignments[i] = assignment
+	}
+	return assignments
+}
+
+// ClassicalRestriction returns the boolean operator obtained by restricting the operator to FALSE and TRUE,
+// and whether the operator returns only FALSE or TRUE for those operands.
+// If the second return value is false, the returned boolean operator converts UNKNOWN results to false.
+func ClassicalRestriction(op func(a Value, b Value) Value) (func(a bool, b bool) bool, bool) {
+	classical := true
+	for _, a := range []bool{false, true} {
+		for _, b := range []bool{false, true} {
+			if op(ConvertFromBool(a), ConvertFromBool(b)) == UNKNOWN {
+				classical = false
+			}
+		}
+	}
+	restricted := func(a bool, b bool) bool {
+		return op(ConvertFromBool(a), ConvertFromBool(b)).ParseBool()
+	}
+	return restricted, classical
+}
+
+// Centroid returns the weighted mean of the numeric representations of the values, in the range [-1, 1].
+// Returns an error if the numbers of values and weights differ, if any weight is negative, NaN or infinite,
+// or if the total weight is zero or overflows.
+func Centroid(values []Value, weights []float64) (float64, error) {
+	if len(values) != len(weights) {
+		return 0, errors.New(fmt.Sprintf("weigh %d values with %d weights: length mismatch", len(values), len(weights)))
+	}
+
+	sum := 0.0
+	total := 0.0
+	for i := 0; i < len(values); i++ {
+		if math.IsNaN(weights[i]) || math.IsInf(weights[i], 0) {
+			return 0, errors.New(fmt.Sprintf("weigh values with weight %v: invalid weight", weights[i]))
+		}
+		if weights[i] < 0 {
+			return 0, errors.New(fmt.Sprintf("weigh values with weight %v: negative weight", weights[i]))
+		}
+		sum += float64(values[i].Int()) * weights[i]
+		total += weights[i]
+	}
+	if total == 0 {
+		return 0, errors.New("weigh values: total weight is zero")
+	}
+	if math.IsInf(total, 0) {
+		return 0, errors.New("weigh values: total weight overflows")
+	}
+	return sum / total, nil
+}
+
+// FlapCount returns the number of times the value changes between consecutive elements in the sequence.
+func FlapCount(values []Value) int {
+	n := 0
+	for i := 1; i < len(values); i++ {
+		if values[i] != values[i-1] {
+			n++
+		}
+	}
+	return n
+}
+
+// IsFlapping returns true if the value changes at least threshold times in the sequence.
+func IsFlapping(values []Value, threshold int) bool {
+	return threshold <= FlapCount(values)
+}
+
+// SafeAll returns TRUE if all values are TRUE, otherwise returns FALSE.
+// It is the same as All except that UNKNOWN is treated as FALSE, so that the result is never UNKNOWN.
+func SafeAll(values []Value) Value {
+	for i := 0; i < len(values); i++ {
+		if values[i] != TRUE {
+			return FALSE
+		}
+	}
+	return TRUE
+}
+
+// OpenAny returns FALSE if all values are FALSE, otherwise returns TRUE.
+// It is the same as Any except that UNKNOWN is treated as TRUE, so that the result is never UNKNOWN.
+func OpenAny(values []Value) Value {
+	for i := 0; i < len(values); i++ {
+		if values[i] != FALSE {
+			return TRUE
+		}
+	}
+	return FALSE
+}
+
+// IsComplete returns true if none of the values is UNKNOWN.
+func IsComplete(values []Value) bool {
+	for i := 0; i < len(values); i++ {
+		if values[i] == UNKNOWN {
+			return false
+		}
+	}
+	return true
+}
+
+// MissingIndices returns the indices of the UNKNOWN values.
+func MissingIndices(values []Value) []int {
+	var indices []int
+	for i := 0; i < len(values); i++ {
+		if values[i] == UNKNOWN {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// ImputeFromNeighbors returns the values with each UNKNOWN replaced by the nearest value that is not UNKNOWN.
+// If the nearest values on both sides are at the same distance, the one on the left is used.
+// Leading and trailing UNKNOWN values are filled from their only side, and they remain UNKNOWN only if
+// no value is definite.
+func ImputeFromNeighbors(values []Value) []Value {
+	result := make([]Value, len(values))
+	left := make([]int, len(values))
+	last := -1
+	for i := 0; i < len(values); i++ {
+		if values[i] != UNKNOWN {
+			last = i
+		}
+		left[i] = last
+	}
+
+	last = -1
+	for i := len(values) - 1; 0 <= i; i-- {
+		if values[i] != UNKNOWN {
+			last = i
+		}
+		l := left[i]
+		r := last
+		switch {
+		case l < 0 && r < 0:
+			result[i] = UNKNOWN
+		case r < 0 || (-1 < l && i-l <= r-i):
+			result[i] = values[l]
+		default:
+			result[i] = values[r]
+		}
+	}
+	return result
+}
+
+// AtLeast returns whether at least n of the values are TRUE.
+// Returns UNKNOWN if the answer depends on what the UNKNOWN values turn out to be.
+func AtLeast(n int, values []Value) Value {
+	trues, unknowns, _ := Count(values)
+	switch {
+	case n <= trues:
+		return TRUE
+	case trues+unknowns < n:
+		return FALSE
+	}
+	return UNKNOWN
+}
+
+// AtMost returns whether at most n of the values are TRUE.
+// Returns UNKNOWN if the answer depends on what the UNKNOWN values turn out to be.
+func AtMost(n int, values []Value) Value {
+	trues, unknowns, _ := Count(values)
+	switch {
+	case trues+unknowns <= n:
+		return TRUE
+	case n < trues:
+		return FALSE
+	}
+	return UNKNOWN
+}
+
+// ExactlyN returns whether exactly n of the values are TRUE.
+// Returns UNKNOWN if the answer depends on what the UNKNOWN values turn out to be.
+func ExactlyN(n int, values []Value) Value {
+	return And(AtLeast(n, values), AtMost(n, values))
+}
+
+// Count returns the numbers of TRUE, UNKNOWN and FALSE values.
+func Count(values []Value) (trues int, unknowns int, falses int) {
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case TRUE:
+			trues++
+		case FALSE:
+			falses++
+		default:
+			unknowns++
+		}
+	}
+	return trues, unknowns, falses
+}
+
+// Pair represents a pair of operands.
+type Pair struct {
+	A Value
+	B Value
+}
+
+// AsymmetricPairs returns the pairs of operands for which the result of the operator changes when the operands
+// are swapped. The operator is commutative if no pairs are returned.
+func AsymmetricPairs(op func(a Value, b Value) Value) []Pair {
+	var pairs []Pair
+	for a := FALSE; a <= TRUE; a++ {
+		for b := FALSE; b <= TRUE; b++ {
+			if op(a, b) != op(b, a) {
+				pairs = append(pairs, Pair{A: a, B: b})
+			}
+		}
+	}
+	return pairs
+}
+
+// CartesianApply returns the matrix of results of applying the operator to every pair of elements of two slices.
+// The element at [i][j] is the result of op(a[i], b[j]).
+func CartesianApply(a []Value, b []Value, op func(x Value, y Value) Value) [][]Value {
+	result := make([][]Value, len(a))
+	for i := 0; i < len(a); i++ {
+		result[i] = make([]Value, len(b))
+		for j := 0; j < len(b); j++ {
+			result[i][j] = op(a[i], b[j])
+		}
+	}
+	return result
+}
+
+// Unanimous returns TRUE if all values are TRUE, returns FALSE if any value is FALSE,
+// and otherwise returns UNKNOWN. An empty slice is unanimous, so it returns TRUE.
+// The result is the same as All.
+func Unanimous(values []Value) Value {
+	return All(values)
+}
+
+// ConsensusColumns returns, for each position, the value held by the most sequences at that position.
+// Returns UNKNOWN for a position at which two or more values are tied.
+// Returns an error if the sequences have different lengths.
+func ConsensusColumns(sequences [][]Value) ([]Value, error) {
+	if len(sequences) < 1 {
+		return []Value{}, nil
+	}
+
+	n := len(sequences[0])
+	for _, seq := range sequences[1:] {
+		if len(seq) != n {
+			return nil, errors.New(fmt.Sprintf("combine sequences of %d and %d values: length mismatch", n, len(seq)))
+		}
+	}
+
+	result := make([]Value, n)
+	for i := 0; i < n; i++ {
+		column := make([]Value, len(sequences))
+		for j, seq := range sequences {
+			column[j] = seq[i]
+		}
+
+		trues, unknowns, falses := Count(column)
+		switch {
+		case unknowns < trues && falses < trues:
+			result[i] = TRUE
+		case unknowns < falses && trues < falses:
+			result[i] = FALSE
+		default:
+			result[i] = UNKNOWN
+		}
+	}
+	return result, nil
+}
+
+// Fingerprint returns a stable 64-bit FNV-1a hash of the values.
+// The hash is computed over the number of values followed by the values packed into two bits each,
+// so equal slices always produce equal fingerprints.
+func Fingerprint(values []Value) uint64 {
+	h := fnv.New64a()
+
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(values)))
+	_, _ = h.Write(length[:])
+
+	_, _ = h.Write(pack(values))
+	return h.Sum64()
+}
+
+func pack(values []Value) []byte {
+	packed := make([]byte, (len(values)+3)/4)
+	for i := 0; i < len(values); i++ {
+		packed[i/4] |= byte(values[i]+1) << (uint(i%4) * 2)
+	}
+	return packed
+}
+
+// Constraint represents the result that an operator must return for a pair of operands.
+type Constraint struct {
+	A      Value
+	B      Value
+	Result Value
+}
+
+// SolveOperator returns an operator that satisfies all the constraints.
+// The result for a pair of operands that is not constrained is UNKNOWN.
+// Returns an error if two constraints require different results for the same pair of operands.
+func SolveOperator(constraints []Constraint) (func(a Value, b Value) Value, error) {
+	var table [3][3]Value
+	var constrained [3][3]bool
+	for _, c := range constraints {
+		i, j := c.A+1, c.B+1
+		if constrained[i][j] && table[i][j] != c.Result {
+			return nil, errors.New(fmt.Sprintf("solve operator at (%s, %s): contradictory results %s and %s", c.A, c.B, table[i][j], c.Result))
+		}
+		table[i][j] = c.Result
+		constrained[i][j] = true
+	}
+
+	op := func(a Value, b Value) Value {
+		return table[a+1][b+1]
+	}
+	return op, nil
+}
+
+// ClampFloat converts a floating-point number to a ternary value by bands.
+// Returns FALSE if x is less than or equal to falseMax, returns TRUE if x is greater than or equal to trueMin,
+// and returns UNKNOWN if x is between them.
+// Returns an error if falseMax is not less than trueMin, or if x is NaN.
+func ClampFloat(x float64, falseMax float64, trueMin float64) (Value, error) {
+	if !(falseMax < trueMin) {
+		return UNKNOWN, errors.New(fmt.Sprintf("clamp with bands (%v, %v): overlapping bands", falseMax, trueMin))
+	}
+	switch {
+	case math.IsNaN(x):
+		return UNKNOWN, &ConversionError{Source: x}
+	case x <= falseMax:
+		return FALSE, nil
+	case trueMin <= x:
+		return TRUE, nil
+	}
+	return UNKNOWN, nil
+}
+
+// FromFreshness converts the age of data to a ternary value.
+// Returns TRUE if the age is less than warnAfter, returns FALSE if it is greater than or equal to staleAfter,
+// and returns UNKNOWN if it is between them.
+// Returns an error if warnAfter is greater than staleAfter.
+func FromFreshness(age time.Duration, warnAfter time.Duration, staleAfter time.Duration) (Value, error) {
+	if staleAfter < warnAfter {
+		return UNKNOWN, errors.New(fmt.Sprintf("check freshness with thresholds (%s, %s): invalid thresholds", warnAfter, staleAfter))
+	}
+	switch {
+	case age < warnAfter:
+		return TRUE, nil
+	case staleAfter <= age:
+		return FALSE, nil
+	}
+	return UNKNOWN, nil
+}
+
+// UnknownCells returns the pairs of operands for which the operator returns UNKNOWN.
+func UnknownCells(op func(a Value, b Value) Value) []Pair {
+	var pairs []Pair
+	for a := FALSE; a <= TRUE; a++ {
+		for b := FALSE; b <= TRUE; b++ {
+			if op(a, b) == UNKNOWN {
+				pairs = append(pairs, Pair{A: a, B: b})
+			}
+		}
+	}
+	return pairs
+}
+
+// Sort sorts the values in place in the order FALSE < UNKNOWN < TRUE.
+func Sort(values []Value) {
+	sort.Slice(values, func(i, j int) bool {
+		return Less(values[i], values[j])
+	})
+}
+
+// SortStable sorts the values in place in the order FALSE < UNKNOWN < TRUE, keeping the original order of
+// equal values.
+func SortStable(values []Value) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return Less(values[i], values[j])
+	})
+}
+
+// Reduce returns the result of folding the values from left to right with the operator,
+// starting from the initial value.
+// Reduce always applies the operator to every value. Short-circuiting is the responsibility of the operator.
+func Reduce(values []Value, op func(a Value, b Value) Value, initial Value) Value {
+	t := initial
+	for i := 0; i < len(values); i++ {
+		t = op(t, values[i])
+	}
+	return t
+}
+
+// FormulasEquivalent returns true if the two formulas return the same value for every assignment of values
+// to the variables.
+// Returns an error if vars is negative or greater than MaxAssignmentVariables.
+func FormulasEquivalent(vars int, f func([]Value) Value, g func([]Value) Value) (bool, error) {
+	assignments := Assignments(vars)
+	if assignments == nil {
+		return false, errors.New(fmt.Sprintf("compare formulas of %d variables: number of variables out of range", vars))
+	}
+	for _, assignment := range assignments {
+		if f(assignment) != g(assignment) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Map returns a new slice of the results of applying the function to each value.
+func Map(values []Value, f func(Value) Value) []Value {
+	result := make([]Value, len(values))
+	for i := 0; i < len(values); i++ {
+		result[i] = f(values[i])
+	}
+	return result
+}
+
+// Filter returns a new slice of the values for which the function returns true.
+func Filter(values []Value, keep func(Value) bool) []Value {
+	result := make([]Value, 0, len(values))
+	for i := 0; i < len(values); i++ {
+		if keep(values[i]) {
+			result = append(result, values[i])
+		}
+	}
+	return result
+}
+
+// Harden returns the boolean of the value, or preferTrue if the value is UNKNOWN.
+func Harden(v Value, preferTrue bool) bool {
+	if v == UNKNOWN {
+		return preferTrue
+	}
+	return v.ParseBool()
+}
+
+// PairwiseApply returns the results of applying the operator to each pair of consecutive values.
+// The result has one fewer element than the values, and is empty if there are fewer than two values.
+func PairwiseApply(values []Value, op func(a Value, b Value) Value) []Value {
+	if len(values) < 2 {
+		return []Value{}
+	}
+	result := make([]Value, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		result[i-1] = op(values[i-1], values[i])
+	}
+	return result
+}
+
+// AllFunc returns the result of logical conjunction on the values of the predicate for all items.
+// The predicate is not called for the items after the result is determined to be FALSE.
+func AllFunc[T any](items []T, pred func(T) Value) Value {
+	t := TRUE
+	for _, item := range items {
+		t = And(t, pred(item))
+		if t == FALSE {
+			return FALSE
+		}
+	}
+	return t
+}
+
+// AnyFunc returns the result of logical disjunction on the values of the predicate for all items.
+// The predicate is not called for the items after the result is determined to be TRUE.
+func AnyFunc[T any](items []T, pred func(T) Value) Value {
+	t := FALSE
+	for _, item := range items {
+		t = Or(t, pred(item))
+		if t == TRUE {
+			return TRUE
+		}
+	}
+	return t
+}
+
+// MajorityWithVeto returns FALSE if any of the values is FALSE.
+// Otherwise, returns TRUE if TRUE values outnumber UNKNOWN values, and returns UNKNOWN
+// if they do not, including when the values are empty.
+func MajorityWithVeto(values []Value) Value {
+	trues := 0
+	unknowns := 0
+	for _, v := range values {
+		switch v {
+		case FALSE:
+			return FALSE
+		case TRUE:
+			trues++
+		default:
+			unknowns++
+		}
+	}
+	if unknowns < trues {
+		return TRUE
+	}
+	return UNKNOWN
+}
+
+// AndThen returns the result of logical conjunction of a and the value returned by b.
+// If a is FALSE, returns FALSE without calling b.
+func AndThen(a Value, b func() Value) Value {
+	if a == FALSE {
+		return FALSE
+	}
+	return And(a, b())
+}
+
+// OrElse returns the result of logical disjunction of a and the value returned by b.
+// If a is TRUE, returns TRUE without calling b.
+func OrElse(a Value, b func() Value) Value {
+	if a == TRUE {
+		return TRUE
+	}
+	return Or(a, b())
+}
+
+// MarshalPacked returns a base64 encoded representation of the values.
+// The encoded bytes are the number of values as an 8-byte big-endian integer followed by
+// the values packed into two bits each, in the same layout as Fingerprint.
+func MarshalPacked(values []Value) string {
+	buf := make([]byte, 8, 8+(len(values)+3)/4)
+	binary.BigEndian.PutUint64(buf, uint64(len(values)))
+	buf = append(buf, pack(values)...)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// UnmarshalPacked returns the values decoded from a string encoded by MarshalPacked.
+func UnmarshalPacked(s string) ([]Value, error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unpack values from %q: invalid base64 encoding", s))
+	}
+	if len(buf) < 8 {
+		return nil, errors.New(fmt.Sprintf("unpack values from %q: missing length", s))
+	}
+
+	n := binary.BigEndian.Uint64(buf[:8])
+	packed := buf[8:]
+	if n > uint64(len(packed))*4 || uint64(len(packed)) != (n+3)/4 {
+		return nil, errors.New(fmt.Sprintf("unpack %d values from %d bytes: length mismatch", n, len(packed)))
+	}
+
+	values := make([]Value, n)
+	for i := range values {
+		code := (packed[i/4] >> (uint(i%4) * 2)) & 3
+		if code == 3 {
+			return nil, errors.New(fmt.Sprintf("unpack value at %d: invalid code %d", i, code))
+		}
+		values[i] = Value(code) - 1
+	}
+	if 0 < n%4 && packed[len(packed)-1]>>(uint(n%4)*2) != 0 {
+		return nil, errors.New(fmt.Sprintf("unpack %d values: non-zero padding", n))
+	}
+	return values, nil
+}
@@ -1,7 +1,18 @@
 package ternary
 
 import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestValue_String(t *testing.T) {
@@ -21,88 +32,4341 @@ func TestValue_String(t *testing.T) {
 	}
 }
 
+var formatTests = []struct {
+	Format string
+	Value  Value
+	Result string
+}{
+	{
+		Format: "%v",
+		Value:  TRUE,
+		Result: "TRUE",
+	},
+	{
+		Format: "%s",
+		Value:  UNKNOWN,
+		Result: "UNKNOWN",
+	},
+	{
+		Format: "%q",
+		Value:  FALSE,
+		Result: "\"FALSE\"",
+	},
+	{
+		Format: "%d",
+		Value:  FALSE,
+		Result: "-1",
+	},
+	{
+		Format: "%d",
+		Value:  UNKNOWN,
+		Result: "0",
+	},
+	{
+		Format: "%d",
+		Value:  TRUE,
+		Result: "1",
+	},
+	{
+		Format: "%c",
+		Value:  FALSE,
+		Result: "F",
+	},
+	{
+		Format: "%c",
+		Value:  UNKNOWN,
+		Result: "U",
+	},
+	{
+		Format: "%c",
+		Value:  TRUE,
+		Result: "T",
+	},
+	{
+		Format: "%8v",
+		Value:  TRUE,
+		Result: "    TRUE",
+	},
+	{
+		Format: "%-8s|",
+		Value:  FALSE,
+		Result: "FALSE   |",
+	},
+	{
+		Format: "%3d",
+		Value:  FALSE,
+		Result: " -1",
+	},
+	{
+		Format: "%-3d|",
+		Value:  TRUE,
+		Result: "1  |",
+	},
+	{
+		Format: "%+d",
+		Value:  TRUE,
+		Result: "+1",
+	},
+	{
+		Format: "%3c",
+		Value:  UNKNOWN,
+		Result: "  U",
+	},
+	{
+		Format: "%-3c|",
+		Value:  TRUE,
+		Result: "T  |",
+	},
+	{
+		Format: "%#v",
+		Value:  TRUE,
+		Result: "ternary.TRUE",
+	},
+	{
+		Format: "%#v",
+		Value:  UNKNOWN,
+		Result: "ternary.UNKNOWN",
+	},
+	{
+		Format: "%#v",
+		Value:  Value(2),
+		Result: "ternary.Value(2)",
+	},
+	{
+		Format: "%-14v|",
+		Value:  FALSE,
+		Result: "FALSE         |",
+	},
+	{
+		Format: "%-#16v|",
+		Value:  FALSE,
+		Result: "ternary.FALSE   |",
+	},
+	{
+		Format: "%x",
+		Value:  TRUE,
+		Result: "54525545",
+	},
+	{
+		Format: "%z",
+		Value:  TRUE,
+		Result: "%!z(ternary.Value=TRUE)",
+	},
+}
+
+func TestValue_Format(t *testing.T) {
+	for _, test := range formatTests {
+		s := fmt.Sprintf(test.Format, test.Value)
+		if s != test.Result {
+			t.Errorf("string = %q, want %q for %q with %s", s, test.Result, test.Format, test.Value.String())
+		}
+	}
+
+	s := fmt.Sprintf("%v", []Value{FALSE, UNKNOWN, TRUE})
+	if s != "[FALSE UNKNOWN TRUE]" {
+		t.Errorf("string = %q, want %q for a slice", s, "[FALSE UNKNOWN TRUE]")
+	}
+
+	s = fmt.Sprintf("%#v", struct{ V Value }{V: TRUE})
+	if s != "struct { V ternary.Value }{V:ternary.TRUE}" {
+		t.Errorf("string = %q, want %q for a struct", s, "struct { V ternary.Value }{V:ternary.TRUE}")
+	}
+}
+
+var isValidTests = []struct {
+	Value      Value
+	IsValid    bool
+	Normalized Value
+}{
+	{
+		Value:      Value(-128),
+		IsValid:    false,
+		Normalized: FALSE,
+	},
+	{
+		Value:      Value(-2),
+		IsValid:    false,
+		Normalized: FALSE,
+	},
+	{
+		Value:      FALSE,
+		IsValid:    true,
+		Normalized: FALSE,
+	},
+	{
+		Value:      UNKNOWN,
+		IsValid:    true,
+		Normalized: UNKNOWN,
+	},
+	{
+		Value:      TRUE,
+		IsValid:    true,
+		Normalized: TRUE,
+	},
+	{
+		Value:      Value(2),
+		IsValid:    false,
+		Normalized: TRUE,
+	},
+	{
+		Value:      Value(100),
+		IsValid:    false,
+		Normalized: TRUE,
+	},
+}
+
+func TestValue_IsValid(t *testing.T) {
+	for _, test := range isValidTests {
+		b := test.Value.IsValid()
+		if b != test.IsValid {
+			t.Errorf("bool value = %t, want %t for %d", b, test.IsValid, test.Value)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, test := range isValidTests {
+		v := Normalize(test.Value)
+		if v != test.Normalized {
+			t.Errorf("ternary = %s, want %s for %d", v, test.Normalized, test.Value)
+		}
+	}
+}
+
+func TestValue_MarshalJSON(t *testing.T) {
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		expect := "\"" + v.String() + "\""
+		if string(b) != expect {
+			t.Errorf("json = %s, want %s for %s", b, expect, v)
+		}
+	}
+}
+
+var unmarshalJSONTests = []struct {
+	JSON   string
+	Result Value
+	Err    string
+}{
+	{
+		JSON:   `"TRUE"`,
+		Result: TRUE,
+	},
+	{
+		JSON:   `"unknown"`,
+		Result: UNKNOWN,
+	},
+	{
+		JSON:   `"False"`,
+		Result: FALSE,
+	},
+	{
+		JSON:   `-1`,
+		Result: FALSE,
+	},
+	{
+		JSON:   `0`,
+		Result: UNKNOWN,
+	},
+	{
+		JSON:   `1`,
+		Result: TRUE,
+	},
+	{
+		JSON:   `1.0`,
+		Result: TRUE,
+	},
+	{
+		JSON:   `-1e0`,
+		Result: FALSE,
+	},
+	{
+		JSON:   `0.0`,
+		Result: UNKNOWN,
+	},
+	{
+		JSON:   `true`,
+		Result: TRUE,
+	},
+	{
+		JSON:   `false`,
+		Result: FALSE,
+	},
+	{
+		JSON:   `null`,
+		Result: UNKNOWN,
+	},
+	{
+		JSON: `"maybe"`,
+		Err:  "convert from \"maybe\": invalid value",
+	},
+	{
+		JSON: `2`,
+		Err:  "convert from 2: invalid value",
+	},
+	{
+		JSON: `1.5`,
+		Err:  "convert from 1.5: invalid value",
+	},
+	{
+		JSON: `1e999`,
+		Err:  "convert from 1e999: invalid value",
+	},
+}
+
+func TestValue_UnmarshalJSON(t *testing.T) {
+	for _, test := range unmarshalJSONTests {
+		v := TRUE
+		if test.Result == TRUE {
+			v = FALSE
+		}
+		err := json.Unmarshal([]byte(test.JSON), &v)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s", err.Error(), test.Err, test.JSON)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s", test.Err, test.JSON)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %s", v, test.Result, test.JSON)
+		}
+	}
+}
+
+func TestValue_JSONRoundTrip(t *testing.T) {
+	type config struct {
+		Name    string
+		Enabled Value
+		Flags   []Value
+	}
+
+	c := config{
+		Name:    "test",
+		Enabled: UNKNOWN,
+		Flags:   []Value{TRUE, FALSE},
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+	expect := `{"Name":"test","Enabled":"UNKNOWN","Flags":["TRUE","FALSE"]}`
+	if string(b) != expect {
+		t.Errorf("json = %s, want %s", b, expect)
+	}
+
+	var decoded config
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Errorf("decoded = %v, want %v", decoded, c)
+	}
+}
+
+func TestValue_MarshalText(t *testing.T) {
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		var m encoding.TextMarshaler = v
+		text, err := m.MarshalText()
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if string(text) != v.String() {
+			t.Errorf("text = %q, want %q for %s", text, v.String(), v)
+		}
+
+		r := UNKNOWN
+		if v == UNKNOWN {
+			r = TRUE
+		}
+		var u encoding.TextUnmarshaler = &r
+		if err = u.UnmarshalText(text); err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if r != v {
+			t.Errorf("ternary = %s, want %s for round trip", r, v)
+		}
+	}
+
+	r := TRUE
+	err := r.UnmarshalText([]byte("invalid"))
+	if err == nil {
+		t.Errorf("no error, want error %q for %q", "convert from \"invalid\": invalid value", "invalid")
+	} else if err.Error() != "convert from \"invalid\": invalid value" {
+		t.Errorf("error = %q, want error %q for %q", err.Error(), "convert from \"invalid\": invalid value", "invalid")
+	}
+	if r != TRUE {
+		t.Errorf("ternary = %s, want %s unchanged after error", r, TRUE)
+	}
+
+	keys := map[Value]int{FALSE: -1, TRUE: 1}
+	b, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+	if string(b) != `{"FALSE":-1,"TRUE":1}` {
+		t.Errorf("json = %s, want %s for map keys", b, `{"FALSE":-1,"TRUE":1}`)
+	}
+	var decoded map[Value]int
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+	if !reflect.DeepEqual(decoded, keys) {
+		t.Errorf("decoded = %v, want %v for map keys", decoded, keys)
+	}
+}
+
 func TestValue_Int(t *testing.T) {
 	i := FALSE.Int()
 	if i != -1 {
 		t.Errorf("int value = %d, want %d for %s", i, -1, FALSE)
 	}
 
-	i = UNKNOWN.Int()
-	if i != 0 {
-		t.Errorf("int value = %d, want %d for %s", i, 0, UNKNOWN)
+	i = UNKNOWN.Int()
+	if i != 0 {
+		t.Errorf("int value = %d, want %d for %s", i, 0, UNKNOWN)
+	}
+
+	i = TRUE.Int()
+	if i != 1 {
+		t.Errorf("int value = %d, want %d for %s", i, 1, TRUE)
+	}
+}
+
+func BenchmarkValue_Int(b *testing.B) {
+	values := []Value{FALSE, UNKNOWN, TRUE}
+	var sum int64
+	for i := 0; i < b.N; i++ {
+		sum += values[i%3].Int()
+	}
+	_ = sum
+}
+
+func TestValue_ParseBool(t *testing.T) {
+	b := FALSE.ParseBool()
+	if b != false {
+		t.Errorf("bool value = %t, want %t for %s", b, false, FALSE)
+	}
+
+	b = UNKNOWN.ParseBool()
+	if b != false {
+		t.Errorf("bool value = %t, want %t for %s", b, false, UNKNOWN)
+	}
+
+	b = TRUE.ParseBool()
+	if b != true {
+		t.Errorf("bool value = %t, want %t for %s", b, true, TRUE)
+	}
+}
+
+func TestValue_IconName(t *testing.T) {
+	s := FALSE.IconName()
+	if s != "x-circle" {
+		t.Errorf("icon name = %q, want %q for %s", s, "x-circle", FALSE)
+	}
+
+	s = UNKNOWN.IconName()
+	if s != "help-circle" {
+		t.Errorf("icon name = %q, want %q for %s", s, "help-circle", UNKNOWN)
+	}
+
+	s = TRUE.IconName()
+	if s != "check-circle" {
+		t.Errorf("icon name = %q, want %q for %s", s, "check-circle", TRUE)
+	}
+
+	defaultIconNames := IconNames
+	defer func() { IconNames = defaultIconNames }()
+
+	IconNames = map[Value]string{
+		FALSE:   "thumbs-down",
+		UNKNOWN: "question",
+		TRUE:    "thumbs-up",
+	}
+
+	s = UNKNOWN.IconName()
+	if s != "question" {
+		t.Errorf("icon name = %q, want %q for %s", s, "question", UNKNOWN)
+	}
+}
+
+var statusEmojiTests = []struct {
+	Value    Value
+	Previous Value
+	Result   string
+}{
+	{
+		Value:    TRUE,
+		Previous: UNKNOWN,
+		Result:   "🟢⬆️",
+	},
+	{
+		Value:    UNKNOWN,
+		Previous: FALSE,
+		Result:   "🟡⬆️",
+	},
+	{
+		Value:    FALSE,
+		Previous: TRUE,
+		Result:   "🔴⬇️",
+	},
+	{
+		Value:    UNKNOWN,
+		Previous: TRUE,
+		Result:   "🟡⬇️",
+	},
+	{
+		Value:    TRUE,
+		Previous: TRUE,
+		Result:   "🟢➡️",
+	},
+	{
+		Value:    FALSE,
+		Previous: FALSE,
+		Result:   "🔴➡️",
+	},
+}
+
+func TestValue_StatusEmoji(t *testing.T) {
+	for _, test := range statusEmojiTests {
+		s := test.Value.StatusEmoji(test.Previous)
+		if s != test.Result {
+			t.Errorf("emoji = %q, want %q for %s from %s", s, test.Result, test.Value, test.Previous)
+		}
+	}
+}
+
+func TestValue_Byte(t *testing.T) {
+	b := FALSE.Byte()
+	if b != 'F' {
+		t.Errorf("byte = %q, want %q for %s", b, 'F', FALSE)
+	}
+
+	b = UNKNOWN.Byte()
+	if b != 'U' {
+		t.Errorf("byte = %q, want %q for %s", b, 'U', UNKNOWN)
+	}
+
+	b = TRUE.Byte()
+	if b != 'T' {
+		t.Errorf("byte = %q, want %q for %s", b, 'T', TRUE)
+	}
+}
+
+var isDesignatedTests = []struct {
+	Value  Value
+	Policy DesignationPolicy
+	Result bool
+}{
+	{
+		Value:  FALSE,
+		Policy: DesignateTrue,
+		Result: false,
+	},
+	{
+		Value:  UNKNOWN,
+		Policy: DesignateTrue,
+		Result: false,
+	},
+	{
+		Value:  TRUE,
+		Policy: DesignateTrue,
+		Result: true,
+	},
+	{
+		Value:  FALSE,
+		Policy: DesignateTrueUnknown,
+		Result: false,
+	},
+	{
+		Value:  UNKNOWN,
+		Policy: DesignateTrueUnknown,
+		Result: true,
+	},
+	{
+		Value:  TRUE,
+		Policy: DesignateTrueUnknown,
+		Result: true,
+	},
+}
+
+func TestValue_IsDesignated(t *testing.T) {
+	for _, test := range isDesignatedTests {
+		b := test.Value.IsDesignated(test.Policy)
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for %s with policy %d", b, test.Result, test.Value, test.Policy)
+		}
+	}
+}
+
+var convertFromStringTests = []struct {
+	Str    string
+	Result Value
+	Err    string
+}{
+	{
+		Str:    "false",
+		Result: FALSE,
+	},
+	{
+		Str:    "unknown",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "true",
+		Result: TRUE,
+	},
+	{
+		Str:    "-1",
+		Result: FALSE,
+	},
+	{
+		Str:    "0",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "1",
+		Result: TRUE,
+	},
+	{
+		Str: "ParseError",
+		Err: "convert from \"ParseError\": invalid value",
+	},
+}
+
+func TestConvertFromString(t *testing.T) {
+	for _, test := range convertFromStringTests {
+		v, err := ConvertFromString(test.Str)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s", err.Error(), test.Err, test.Str)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s", test.Err, test.Str)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %q", v, test.Result, test.Str)
+		}
+	}
+}
+
+var parseLooseTests = []struct {
+	Str    string
+	Result Value
+	Err    string
+}{
+	{
+		Str:    "yes",
+		Result: TRUE,
+	},
+	{
+		Str:    " On ",
+		Result: TRUE,
+	},
+	{
+		Str:    "t",
+		Result: TRUE,
+	},
+	{
+		Str:    "Y",
+		Result: TRUE,
+	},
+	{
+		Str:    "no",
+		Result: FALSE,
+	},
+	{
+		Str:    "OFF",
+		Result: FALSE,
+	},
+	{
+		Str:    "f",
+		Result: FALSE,
+	},
+	{
+		Str:    "n",
+		Result: FALSE,
+	},
+	{
+		Str:    "maybe",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "NULL",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "nil",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "NA",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "?",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "true",
+		Result: TRUE,
+	},
+	{
+		Str:    "\tFALSE\n",
+		Result: FALSE,
+	},
+	{
+		Str:    "unknown",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "-1",
+		Result: FALSE,
+	},
+	{
+		Str:    "0",
+		Result: UNKNOWN,
+	},
+	{
+		Str:    "1",
+		Result: TRUE,
+	},
+	{
+		Str: " sometimes ",
+		Err: "convert from \" sometimes \": invalid value",
+	},
+}
+
+func TestParseLoose(t *testing.T) {
+	for _, test := range parseLooseTests {
+		v, err := ParseLoose(test.Str)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %q", err.Error(), test.Err, test.Str)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %q", test.Err, test.Str)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %q", v, test.Result, test.Str)
+		}
+	}
+}
+
+var convertFromInt64Tests = []struct {
+	Int    int64
+	Result Value
+	Err    string
+}{
+	{
+		Int:    -1,
+		Result: FALSE,
+	},
+	{
+		Int:    0,
+		Result: UNKNOWN,
+	},
+	{
+		Int:    1,
+		Result: TRUE,
+	},
+	{
+		Int: 12345,
+		Err: "convert from 12345: invalid value",
+	},
+}
+
+func TestConvertFromInt64(t *testing.T) {
+	for _, test := range convertFromInt64Tests {
+		v, err := ConvertFromInt64(test.Int)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %d", err.Error(), test.Err, test.Int)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %d", test.Err, test.Int)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %d", v, test.Result, test.Int)
+		}
+	}
+}
+
+var convertFromASCIITests = []struct {
+	Byte   byte
+	Result Value
+	Err    string
+}{
+	{
+		Byte:   'F',
+		Result: FALSE,
+	},
+	{
+		Byte:   'U',
+		Result: UNKNOWN,
+	},
+	{
+		Byte:   'T',
+		Result: TRUE,
+	},
+	{
+		Byte:   'f',
+		Result: FALSE,
+	},
+	{
+		Byte:   'u',
+		Result: UNKNOWN,
+	},
+	{
+		Byte:   't',
+		Result: TRUE,
+	},
+	{
+		Byte: 'X',
+		Err:  "convert from 'X': invalid value",
+	},
+}
+
+func TestConvertFromASCII(t *testing.T) {
+	for _, test := range convertFromASCIITests {
+		v, err := ConvertFromASCII(test.Byte)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %q", err.Error(), test.Err, test.Byte)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %q", test.Err, test.Byte)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %q", v, test.Result, test.Byte)
+		}
+		if v.Byte() != test.Byte && v.Byte() != test.Byte-('a'-'A') {
+			t.Errorf("byte = %q, want round trip for %q", v.Byte(), test.Byte)
+		}
+	}
+}
+
+var symbolTests = []struct {
+	Value  Value
+	Symbol string
+	Rune   rune
+}{
+	{
+		Value:  FALSE,
+		Symbol: "F",
+		Rune:   'F',
+	},
+	{
+		Value:  UNKNOWN,
+		Symbol: "U",
+		Rune:   'U',
+	},
+	{
+		Value:  TRUE,
+		Symbol: "T",
+		Rune:   'T',
+	},
+}
+
+func TestValue_Symbol(t *testing.T) {
+	for _, test := range symbolTests {
+		s := test.Value.Symbol()
+		if s != test.Symbol {
+			t.Errorf("string = %q, want %q for %s.Symbol()", s, test.Symbol, test.Value)
+		}
+		r := test.Value.Rune()
+		if r != test.Rune {
+			t.Errorf("rune = %q, want %q for %s.Rune()", r, test.Rune, test.Value)
+		}
+	}
+}
+
+var convertFromRuneTests = []struct {
+	Rune   rune
+	Result Value
+	Err    string
+}{
+	{
+		Rune:   'F',
+		Result: FALSE,
+	},
+	{
+		Rune:   'f',
+		Result: FALSE,
+	},
+	{
+		Rune:   'U',
+		Result: UNKNOWN,
+	},
+	{
+		Rune:   'u',
+		Result: UNKNOWN,
+	},
+	{
+		Rune:   'T',
+		Result: TRUE,
+	},
+	{
+		Rune:   't',
+		Result: TRUE,
+	},
+	{
+		Rune:   'x',
+		Result: UNKNOWN,
+		Err:    "convert from 'x': invalid value",
+	},
+	{
+		Rune:   'Ｔ',
+		Result: UNKNOWN,
+		Err:    "convert from 'Ｔ': invalid value",
+	},
+	{
+		Rune:   'T' + 256,
+		Result: UNKNOWN,
+		Err:    "convert from 'Ŕ': invalid value",
+	},
+}
+
+func TestConvertFromRune(t *testing.T) {
+	for _, test := range convertFromRuneTests {
+		v, err := ConvertFromRune(test.Rune)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %q", err.Error(), test.Err, test.Rune)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %q", test.Err, test.Rune)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %q", v, test.Result, test.Rune)
+		}
+		if v.Rune() != test.Rune && v.Rune() != test.Rune-('a'-'A') {
+			t.Errorf("rune = %q, want round trip for %q", v.Rune(), test.Rune)
+		}
+	}
+
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		r, err := ConvertFromRune(v.Rune())
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if r != v {
+			t.Errorf("ternary = %s, want %s for %q", r, v, v.Rune())
+		}
+	}
+}
+
+var convertFromFloat64Tests = []struct {
+	Float  float64
+	Result Value
+	Err    string
+}{
+	{
+		Float:  -1,
+		Result: FALSE,
+	},
+	{
+		Float:  0,
+		Result: UNKNOWN,
+	},
+	{
+		Float:  math.Copysign(0, -1),
+		Result: UNKNOWN,
+	},
+	{
+		Float:  1,
+		Result: TRUE,
+	},
+	{
+		Float: 0.5,
+		Err:   "convert from 0.5: invalid value",
+	},
+	{
+		Float: math.NaN(),
+		Err:   "convert from NaN: invalid value",
+	},
+	{
+		Float: math.Inf(1),
+		Err:   "convert from +Inf: invalid value",
+	},
+}
+
+func TestConvertFromFloat64(t *testing.T) {
+	for _, test := range convertFromFloat64Tests {
+		v, err := ConvertFromFloat64(test.Float)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %v", err.Error(), test.Err, test.Float)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %v", test.Err, test.Float)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %v", v, test.Result, test.Float)
+		}
+	}
+}
+
+var clampFromInt64Tests = []struct {
+	Int    int64
+	Result Value
+}{
+	{
+		Int:    math.MinInt64,
+		Result: FALSE,
+	},
+	{
+		Int:    -5,
+		Result: FALSE,
+	},
+	{
+		Int:    -1,
+		Result: FALSE,
+	},
+	{
+		Int:    0,
+		Result: UNKNOWN,
+	},
+	{
+		Int:    1,
+		Result: TRUE,
+	},
+	{
+		Int:    7,
+		Result: TRUE,
+	},
+	{
+		Int:    math.MaxInt64,
+		Result: TRUE,
+	},
+}
+
+func TestClampFromInt64(t *testing.T) {
+	for _, test := range clampFromInt64Tests {
+		v := ClampFromInt64(test.Int)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %d", v, test.Result, test.Int)
+		}
+	}
+}
+
+func TestConvertFromBool(t *testing.T) {
+	r := ConvertFromBool(false)
+	if r != FALSE {
+		t.Errorf("ternary = %s, want %s for %t", r, FALSE, false)
+	}
+
+	r = ConvertFromBool(true)
+	if r != TRUE {
+		t.Errorf("ternary = %s, want %s for %t", r, TRUE, true)
+	}
+}
+
+var equalTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+}
+
+func TestEqual(t *testing.T) {
+	for _, test := range equalTests {
+		v := Equal(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"equal(%s, %s)\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var compareTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range compareTests {
+		v := Compare(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s compare %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+func TestLess(t *testing.T) {
+	b := Less(FALSE, TRUE)
+	if b != true {
+		t.Errorf("bool value = %t, want %t for \"%s less %s\"", b, true, FALSE, TRUE)
+	}
+
+	b = Less(TRUE, FALSE)
+	if b != false {
+		t.Errorf("bool value = %t, want %t for \"%s less %s\"", b, false, TRUE, FALSE)
+	}
+
+	b = Less(UNKNOWN, UNKNOWN)
+	if b != false {
+		t.Errorf("bool value = %t, want %t for \"%s less %s\"", b, false, UNKNOWN, UNKNOWN)
+	}
+
+	values := []Value{TRUE, FALSE, UNKNOWN, TRUE, FALSE}
+	sort.Slice(values, func(i, j int) bool { return Less(values[i], values[j]) })
+	expect := []Value{FALSE, FALSE, UNKNOWN, TRUE, TRUE}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("values = %s, want %s after sort", values, expect)
+	}
+}
+
+func TestGreater(t *testing.T) {
+	b := Greater(TRUE, UNKNOWN)
+	if b != true {
+		t.Errorf("bool value = %t, want %t for \"%s greater %s\"", b, true, TRUE, UNKNOWN)
+	}
+
+	b = Greater(FALSE, UNKNOWN)
+	if b != false {
+		t.Errorf("bool value = %t, want %t for \"%s greater %s\"", b, false, FALSE, UNKNOWN)
+	}
+
+	b = Greater(TRUE, TRUE)
+	if b != false {
+		t.Errorf("bool value = %t, want %t for \"%s greater %s\"", b, false, TRUE, TRUE)
+	}
+}
+
+var notTests = []struct {
+	Value  Value
+	Result Value
+}{
+	{
+		Value:  FALSE,
+		Result: TRUE,
+	},
+	{
+		Value:  TRUE,
+		Result: FALSE,
+	},
+	{
+		Value:  UNKNOWN,
+		Result: UNKNOWN,
+	},
+}
+
+func TestNot(t *testing.T) {
+	for _, test := range notTests {
+		v := Not(test.Value)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"not %s\"", v, test.Result, test.Value)
+		}
+	}
+}
+
+var andTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestAnd(t *testing.T) {
+	for _, test := range andTests {
+		v := And(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s and %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var orTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestOr(t *testing.T) {
+	for _, test := range orTests {
+		v := Or(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s or %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var impTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+}
+
+func TestImp(t *testing.T) {
+	for _, test := range impTests {
+		v := Imp(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s imp %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var nimpTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestNimp(t *testing.T) {
+	for _, test := range nimpTests {
+		v := Nimp(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s nimp %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var diff2Tests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestDiff2(t *testing.T) {
+	for _, test := range diff2Tests {
+		v := Diff2(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s diff2 %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var cimpTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestCimp(t *testing.T) {
+	for _, test := range cimpTests {
+		v := Cimp(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s cimp %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var cnimpTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestCnimp(t *testing.T) {
+	for _, test := range cnimpTests {
+		v := Cnimp(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s cnimp %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var godelImpTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestGodelImp(t *testing.T) {
+	for _, test := range godelImpTests {
+		v := GodelImp(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s godel imp %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var eqvTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+}
+
+func TestEqv(t *testing.T) {
+	for _, test := range eqvTests {
+		v := Eqv(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s eqv %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var xorTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestXor(t *testing.T) {
+	for _, test := range xorTests {
+		v := Xor(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s xor %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var strokeTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestStroke(t *testing.T) {
+	for _, test := range strokeTests {
+		v := Stroke(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s stroke %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var daggerTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+}
+
+func TestDagger(t *testing.T) {
+	for _, test := range daggerTests {
+		v := Dagger(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s dagger %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var productAndTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestProductAnd(t *testing.T) {
+	for _, test := range productAndTests {
+		v := ProductAnd(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s product and %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var weakEqvTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: TRUE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestWeakEqv(t *testing.T) {
+	for _, test := range weakEqvTests {
+		v := WeakEqv(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s weak eqv %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var agreeOrUnknownTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result Value
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: FALSE,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: FALSE,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: UNKNOWN,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: TRUE,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: TRUE,
+	},
+}
+
+func TestAgreeOrUnknown(t *testing.T) {
+	for _, test := range agreeOrUnknownTests {
+		v := AgreeOrUnknown(test.Value1, test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for \"%s agree %s\"", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+func TestMajority(t *testing.T) {
+	values := []Value{FALSE, UNKNOWN, TRUE}
+	for _, a := range values {
+		for _, b := range values {
+			for _, c := range values {
+				trues := 0
+				falses := 0
+				for _, v := range []Value{a, b, c} {
+					switch v {
+					case TRUE:
+						trues++
+					case FALSE:
+						falses++
+					}
+				}
+				expect := UNKNOWN
+				if 2 <= trues {
+					expect = TRUE
+				} else if 2 <= falses {
+					expect = FALSE
+				}
+
+				v := Majority(a, b, c)
+				if v != expect {
+					t.Errorf("ternary = %s, want %s for \"majority(%s, %s, %s)\"", v, expect, a, b, c)
+				}
+			}
+		}
+	}
+
+	v := Majority(FALSE, UNKNOWN, TRUE)
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for \"majority(%s, %s, %s)\"", v, UNKNOWN, FALSE, UNKNOWN, TRUE)
+	}
+}
+
+var allTests = []struct {
+	ValueList []Value
+	Result    Value
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE},
+		Result:    TRUE,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    UNKNOWN,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE},
+		Result:    FALSE,
+	},
+	{
+		ValueList: []Value{},
+		Result:    TRUE,
+	},
+}
+
+func TestAll(t *testing.T) {
+	for _, test := range allTests {
+		v := All(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for all \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+var anyTests = []struct {
+	ValueList []Value
+	Result    Value
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE},
+		Result:    TRUE,
+	},
+	{
+		ValueList: []Value{FALSE, UNKNOWN, FALSE},
+		Result:    UNKNOWN,
+	},
+	{
+		ValueList: []Value{FALSE, FALSE, FALSE},
+		Result:    FALSE,
+	},
+	{
+		ValueList: []Value{},
+		Result:    FALSE,
+	},
+}
+
+func TestAny(t *testing.T) {
+	for _, test := range anyTests {
+		v := Any(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for any \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestAndAll(t *testing.T) {
+	for _, test := range allTests {
+		v := AndAll(test.ValueList...)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for and all \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+
+	v := AndAll()
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s for and all with no arguments", v, TRUE)
+	}
+
+	v = AndAll(TRUE, UNKNOWN)
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for \"and all(%s, %s)\"", v, UNKNOWN, TRUE, UNKNOWN)
+	}
+}
+
+func TestOrAny(t *testing.T) {
+	for _, test := range anyTests {
+		v := OrAny(test.ValueList...)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for or any \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+
+	v := OrAny()
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s for or any with no arguments", v, FALSE)
+	}
+
+	v = OrAny(FALSE, UNKNOWN)
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for \"or any(%s, %s)\"", v, UNKNOWN, FALSE, UNKNOWN)
+	}
+}
+
+var firstFalseAtTests = []struct {
+	ValueList []Value
+	Result    Value
+	Index     int
+}{
+	{
+		ValueList: []Value{TRUE, FALSE, UNKNOWN, FALSE},
+		Result:    FALSE,
+		Index:     1,
+	},
+	{
+		ValueList: []Value{FALSE},
+		Result:    FALSE,
+		Index:     0,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    UNKNOWN,
+		Index:     -1,
+	},
+	{
+		ValueList: []Value{TRUE, TRUE},
+		Result:    TRUE,
+		Index:     -1,
+	},
+	{
+		ValueList: []Value{},
+		Result:    TRUE,
+		Index:     -1,
+	},
+}
+
+func TestFirstFalse(t *testing.T) {
+	for _, test := range firstFalseAtTests {
+		v := FirstFalse(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for first false \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestFirstFalseAt(t *testing.T) {
+	for _, test := range firstFalseAtTests {
+		v, idx := FirstFalseAt(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for first false \"%s\"", v, test.Result, test.ValueList)
+		}
+		if idx != test.Index {
+			t.Errorf("index = %d, want %d for first false \"%s\"", idx, test.Index, test.ValueList)
+		}
+	}
+}
+
+var isValidArgumentTests = []struct {
+	Premises   []Value
+	Conclusion Value
+	Policy     DesignationPolicy
+	Result     bool
+}{
+	{
+		Premises:   []Value{TRUE, TRUE},
+		Conclusion: TRUE,
+		Policy:     DesignateTrue,
+		Result:     true,
+	},
+	{
+		Premises:   []Value{TRUE, TRUE},
+		Conclusion: FALSE,
+		Policy:     DesignateTrue,
+		Result:     false,
+	},
+	{
+		Premises:   []Value{TRUE},
+		Conclusion: UNKNOWN,
+		Policy:     DesignateTrue,
+		Result:     false,
+	},
+	{
+		Premises:   []Value{TRUE},
+		Conclusion: UNKNOWN,
+		Policy:     DesignateTrueUnknown,
+		Result:     true,
+	},
+	{
+		Premises:   []Value{TRUE, UNKNOWN},
+		Conclusion: FALSE,
+		Policy:     DesignateTrue,
+		Result:     true,
+	},
+	{
+		Premises:   []Value{TRUE, UNKNOWN},
+		Conclusion: FALSE,
+		Policy:     DesignateTrueUnknown,
+		Result:     false,
+	},
+	{
+		Premises:   []Value{},
+		Conclusion: FALSE,
+		Policy:     DesignateTrue,
+		Result:     false,
+	},
+}
+
+func TestIsValidArgument(t *testing.T) {
+	for _, test := range isValidArgumentTests {
+		b := IsValidArgument(test.Premises, test.Conclusion, test.Policy)
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for premises %s and conclusion %s with policy %d", b, test.Result, test.Premises, test.Conclusion, test.Policy)
+		}
+	}
+}
+
+var zipTests = []struct {
+	Name     string
+	Values1  []Value
+	Values2  []Value
+	Operator func(Value, Value) Value
+	Result   []Value
+	Err      string
+}{
+	{
+		Name:     "and",
+		Values1:  []Value{TRUE, UNKNOWN, TRUE, FALSE},
+		Values2:  []Value{TRUE, TRUE, FALSE, UNKNOWN},
+		Operator: And,
+		Result:   []Value{TRUE, UNKNOWN, FALSE, FALSE},
+	},
+	{
+		Name:    "xor",
+		Values1: []Value{TRUE, TRUE, FALSE, UNKNOWN},
+		Values2: []Value{TRUE, FALSE, FALSE, TRUE},
+		Operator: func(x Value, y Value) Value {
+			return And(Or(x, y), Not(And(x, y)))
+		},
+		Result: []Value{FALSE, TRUE, FALSE, UNKNOWN},
+	},
+	{
+		Name:     "empty",
+		Values1:  []Value{},
+		Values2:  []Value{},
+		Operator: And,
+		Result:   []Value{},
+	},
+	{
+		Name:     "length mismatch",
+		Values1:  []Value{TRUE, TRUE},
+		Values2:  []Value{TRUE},
+		Operator: And,
+		Err:      "zip 2 and 1 values: length mismatch",
+	},
+}
+
+func TestZip(t *testing.T) {
+	for _, test := range zipTests {
+		v, err := Zip(test.Values1, test.Values2, test.Operator)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("%s: unexpected error: %q", test.Name, err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("%s: error = %q, want error %q", test.Name, err.Error(), test.Err)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("%s: no error, want error %q", test.Name, test.Err)
+			continue
+		}
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("%s: result = %s, want %s", test.Name, v, test.Result)
+		}
+	}
+}
+
+var trueFractionTests = []struct {
+	ValueList []Value
+	Result    float64
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE},
+		Result:    1,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE, TRUE, FALSE, FALSE},
+		Result:    0.4,
+	},
+	{
+		ValueList: []Value{UNKNOWN, UNKNOWN},
+		Result:    math.NaN(),
+	},
+	{
+		ValueList: []Value{},
+		Result:    math.NaN(),
+	},
+}
+
+func TestTrueFraction(t *testing.T) {
+	for _, test := range trueFractionTests {
+		f := TrueFraction(test.ValueList)
+		if math.IsNaN(test.Result) {
+			if !math.IsNaN(f) {
+				t.Errorf("fraction = %f, want NaN for %s", f, test.ValueList)
+			}
+			continue
+		}
+		if f != test.Result {
+			t.Errorf("fraction = %f, want %f for %s", f, test.Result, test.ValueList)
+		}
+	}
+}
+
+var fromQueryPresenceTests = []struct {
+	Present  bool
+	RawValue string
+	Result   Value
+}{
+	{
+		Present:  true,
+		RawValue: "",
+		Result:   TRUE,
+	},
+	{
+		Present:  true,
+		RawValue: "true",
+		Result:   TRUE,
+	},
+	{
+		Present:  true,
+		RawValue: "1",
+		Result:   TRUE,
+	},
+	{
+		Present:  true,
+		RawValue: "false",
+		Result:   FALSE,
+	},
+	{
+		Present:  true,
+		RawValue: "0",
+		Result:   UNKNOWN,
+	},
+	{
+		Present:  true,
+		RawValue: "yes",
+		Result:   TRUE,
+	},
+	{
+		Present:  true,
+		RawValue: "off",
+		Result:   FALSE,
+	},
+	{
+		Present:  true,
+		RawValue: "invalid",
+		Result:   UNKNOWN,
+	},
+	{
+		Present:  false,
+		RawValue: "",
+		Result:   UNKNOWN,
+	},
+}
+
+func TestFromQueryPresence(t *testing.T) {
+	for _, test := range fromQueryPresenceTests {
+		v := FromQueryPresence(test.Present, test.RawValue)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for presence %t and value %q", v, test.Result, test.Present, test.RawValue)
+		}
+	}
+}
+
+var settleTests = []struct {
+	Name    string
+	Initial Value
+	Step    func(Value) Value
+	MaxIter int
+	Result  Value
+	Settled bool
+}{
+	{
+		Name:    "identity",
+		Initial: TRUE,
+		Step:    func(v Value) Value { return v },
+		MaxIter: 10,
+		Result:  TRUE,
+		Settled: true,
+	},
+	{
+		Name:    "raise to unknown",
+		Initial: FALSE,
+		Step:    func(v Value) Value { return Or(v, UNKNOWN) },
+		MaxIter: 10,
+		Result:  UNKNOWN,
+		Settled: true,
+	},
+	{
+		Name:    "oscillate",
+		Initial: TRUE,
+		Step:    Not,
+		MaxIter: 5,
+		Result:  FALSE,
+		Settled: false,
+	},
+	{
+		Name:    "no iteration",
+		Initial: TRUE,
+		Step:    Not,
+		MaxIter: 0,
+		Result:  TRUE,
+		Settled: false,
+	},
+}
+
+func TestSettle(t *testing.T) {
+	for _, test := range settleTests {
+		v, settled := Settle(test.Initial, test.Step, test.MaxIter)
+		if v != test.Result {
+			t.Errorf("%s: ternary = %s, want %s", test.Name, v, test.Result)
+		}
+		if settled != test.Settled {
+			t.Errorf("%s: settled = %t, want %t", test.Name, settled, test.Settled)
+		}
+	}
+}
+
+var fromCountsTests = []struct {
+	TrueCount  int
+	FalseCount int
+	Threshold  int
+	Result     Value
+}{
+	{
+		TrueCount:  3,
+		FalseCount: 1,
+		Threshold:  3,
+		Result:     TRUE,
+	},
+	{
+		TrueCount:  0,
+		FalseCount: 5,
+		Threshold:  3,
+		Result:     FALSE,
+	},
+	{
+		TrueCount:  2,
+		FalseCount: 2,
+		Threshold:  3,
+		Result:     UNKNOWN,
+	},
+	{
+		TrueCount:  4,
+		FalseCount: 3,
+		Threshold:  3,
+		Result:     UNKNOWN,
+	},
+}
+
+func TestFromCounts(t *testing.T) {
+	for _, test := range fromCountsTests {
+		v := FromCounts(test.TrueCount, test.FalseCount, test.Threshold)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for counts (%d, %d) with threshold %d", v, test.Result, test.TrueCount, test.FalseCount, test.Threshold)
+		}
+	}
+}
+
+var fromExitCodeTests = []struct {
+	Code        int
+	UnknownCode int
+	Result      Value
+}{
+	{
+		Code:        0,
+		UnknownCode: 2,
+		Result:      TRUE,
+	},
+	{
+		Code:        2,
+		UnknownCode: 2,
+		Result:      UNKNOWN,
+	},
+	{
+		Code:        1,
+		UnknownCode: 2,
+		Result:      FALSE,
+	},
+	{
+		Code:        127,
+		UnknownCode: 2,
+		Result:      FALSE,
+	},
+}
+
+func TestFromExitCode(t *testing.T) {
+	for _, test := range fromExitCodeTests {
+		v := FromExitCode(test.Code, test.UnknownCode)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for exit code %d with unknown code %d", v, test.Result, test.Code, test.UnknownCode)
+		}
+	}
+}
+
+func TestConsistentAggregation(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		values := make([]Value, r.Intn(10))
+		for j := range values {
+			values[j] = Value(r.Intn(3) - 1)
+		}
+		if !ConsistentAggregation(values) {
+			t.Errorf("bool value = %t, want %t for %s", false, true, values)
+		}
+	}
+}
+
+func TestPartitionBy(t *testing.T) {
+	items := []int{-3, 5, 0, 12, -1, 7, 20}
+	pred := func(i int) Value {
+		switch {
+		case i < 0:
+			return FALSE
+		case i < 10:
+			return UNKNOWN
+		}
+		return TRUE
+	}
+
+	trues, unknowns, falses := PartitionBy(items, pred)
+	if !reflect.DeepEqual(trues, []int{12, 20}) {
+		t.Errorf("trues = %v, want %v", trues, []int{12, 20})
+	}
+	if !reflect.DeepEqual(unknowns, []int{5, 0, 7}) {
+		t.Errorf("unknowns = %v, want %v", unknowns, []int{5, 0, 7})
+	}
+	if !reflect.DeepEqual(falses, []int{-3, -1}) {
+		t.Errorf("falses = %v, want %v", falses, []int{-3, -1})
+	}
+}
+
+var convertFromProbabilityWithTests = []struct {
+	Probability float64
+	Policy      RoundingPolicy
+	Result      Value
+	Err         string
+}{
+	{
+		Probability: 0,
+		Policy:      RoundTowardUnknown,
+		Result:      FALSE,
+	},
+	{
+		Probability: 0.01,
+		Policy:      RoundTowardUnknown,
+		Result:      UNKNOWN,
+	},
+	{
+		Probability: 0.99,
+		Policy:      RoundTowardUnknown,
+		Result:      UNKNOWN,
+	},
+	{
+		Probability: 1,
+		Policy:      RoundTowardUnknown,
+		Result:      TRUE,
+	},
+	{
+		Probability: 0.49,
+		Policy:      RoundTowardDefinite,
+		Result:      FALSE,
+	},
+	{
+		Probability: 0.5,
+		Policy:      RoundTowardDefinite,
+		Result:      UNKNOWN,
+	},
+	{
+		Probability: 0.51,
+		Policy:      RoundTowardDefinite,
+		Result:      TRUE,
+	},
+	{
+		Probability: 0.24,
+		Policy:      RoundNearest,
+		Result:      FALSE,
+	},
+	{
+		Probability: 0.25,
+		Policy:      RoundNearest,
+		Result:      UNKNOWN,
+	},
+	{
+		Probability: 0.75,
+		Policy:      RoundNearest,
+		Result:      UNKNOWN,
+	},
+	{
+		Probability: 0.76,
+		Policy:      RoundNearest,
+		Result:      TRUE,
+	},
+	{
+		Probability: 1.5,
+		Policy:      RoundNearest,
+		Err:         "convert from 1.5: invalid value",
+	},
+	{
+		Probability: math.NaN(),
+		Policy:      RoundNearest,
+		Err:         "convert from NaN: invalid value",
+	},
+	{
+		Probability: 0.5,
+		Policy:      RoundingPolicy(99),
+		Err:         "round with policy 99: invalid policy",
+	},
+}
+
+func TestConvertFromProbabilityWith(t *testing.T) {
+	for _, test := range convertFromProbabilityWithTests {
+		v, err := ConvertFromProbabilityWith(test.Probability, test.Policy)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %v with policy %d", err.Error(), test.Err, test.Probability, test.Policy)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %v with policy %d", test.Err, test.Probability, test.Policy)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %v with policy %d", v, test.Result, test.Probability, test.Policy)
+		}
+	}
+}
+
+var preservesTests = []struct {
+	Name           string
+	Operator       func(Value, Value) Value
+	PreservesTrue  bool
+	PreservesFalse bool
+}{
+	{
+		Name:           "and",
+		Operator:       And,
+		PreservesTrue:  true,
+		PreservesFalse: true,
+	},
+	{
+		Name:           "or",
+		Operator:       Or,
+		PreservesTrue:  true,
+		PreservesFalse: true,
+	},
+	{
+		Name:           "imp",
+		Operator:       Imp,
+		PreservesTrue:  true,
+		PreservesFalse: false,
+	},
+	{
+		Name: "nand",
+		Operator: func(a Value, b Value) Value {
+			return Not(And(a, b))
+		},
+		PreservesTrue:  false,
+		PreservesFalse: false,
+	},
+}
+
+func TestPreservesTrue(t *testing.T) {
+	for _, test := range preservesTests {
+		b := PreservesTrue(test.Operator)
+		if b != test.PreservesTrue {
+			t.Errorf("bool value = %t, want %t for %s", b, test.PreservesTrue, test.Name)
+		}
+	}
+}
+
+func TestPreservesFalse(t *testing.T) {
+	for _, test := range preservesTests {
+		b := PreservesFalse(test.Operator)
+		if b != test.PreservesFalse {
+			t.Errorf("bool value = %t, want %t for %s", b, test.PreservesFalse, test.Name)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	vars := map[string]Value{
+		"a": TRUE,
+		"b": FALSE,
+	}
+
+	v := Get(vars, "a")
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s for %q", v, TRUE, "a")
+	}
+
+	v = Get(vars, "b")
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s for %q", v, FALSE, "b")
+	}
+
+	v = Get(vars, "c")
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for %q", v, UNKNOWN, "c")
+	}
+
+	v = Get(nil, "a")
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for %q in nil map", v, UNKNOWN, "a")
+	}
+}
+
+var substituteTests = []struct {
+	Vars   map[string]Value
+	Result Value
+}{
+	{
+		Vars:   map[string]Value{"a": TRUE, "b": FALSE, "c": TRUE},
+		Result: TRUE,
+	},
+	{
+		Vars:   map[string]Value{"a": TRUE, "b": TRUE, "c": FALSE},
+		Result: FALSE,
+	},
+	{
+		Vars:   map[string]Value{"a": TRUE},
+		Result: UNKNOWN,
+	},
+	{
+		Vars:   map[string]Value{"a": FALSE},
+		Result: FALSE,
+	},
+}
+
+func TestSubstitute(t *testing.T) {
+	template := func(vars map[string]Value) Value {
+		return And(Get(vars, "a"), Or(Not(Get(vars, "b")), Get(vars, "c")))
+	}
+
+	for _, test := range substituteTests {
+		v := Substitute(template, test.Vars)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %v", v, test.Result, test.Vars)
+		}
+	}
+}
+
+func TestTableDiff(t *testing.T) {
+	var and [3][3]Value
+	var or [3][3]Value
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			and[i][j] = And(Value(i-1), Value(j-1))
+			or[i][j] = Or(Value(i-1), Value(j-1))
+		}
+	}
+
+	expect := []string{
+		"at (FALSE, UNKNOWN): got FALSE want UNKNOWN",
+		"at (FALSE, TRUE): got FALSE want TRUE",
+		"at (UNKNOWN, FALSE): got FALSE want UNKNOWN",
+		"at (UNKNOWN, TRUE): got UNKNOWN want TRUE",
+		"at (TRUE, FALSE): got FALSE want TRUE",
+		"at (TRUE, UNKNOWN): got UNKNOWN want TRUE",
+	}
+	diff := TableDiff(and, or)
+	if !reflect.DeepEqual(diff, expect) {
+		t.Errorf("diff = %q, want %q", diff, expect)
+	}
+
+	diff = TableDiff(and, and)
+	if len(diff) != 0 {
+		t.Errorf("diff = %q, want no differences", diff)
+	}
+}
+
+var decideWithQuorumTests = []struct {
+	ValueList   []Value
+	MinDefinite int
+	Result      Value
+}{
+	{
+		ValueList:   []Value{TRUE, TRUE, UNKNOWN, UNKNOWN, UNKNOWN},
+		MinDefinite: 3,
+		Result:      UNKNOWN,
+	},
+	{
+		ValueList:   []Value{TRUE, TRUE, FALSE, UNKNOWN, UNKNOWN},
+		MinDefinite: 3,
+		Result:      TRUE,
+	},
+	{
+		ValueList:   []Value{FALSE, TRUE, FALSE, UNKNOWN},
+		MinDefinite: 3,
+		Result:      FALSE,
+	},
+	{
+		ValueList:   []Value{TRUE, FALSE, UNKNOWN, TRUE, FALSE},
+		MinDefinite: 4,
+		Result:      UNKNOWN,
+	},
+	{
+		ValueList:   []Value{},
+		MinDefinite: 0,
+		Result:      UNKNOWN,
+	},
+}
+
+func TestDecideWithQuorum(t *testing.T) {
+	for _, test := range decideWithQuorumTests {
+		v := DecideWithQuorum(test.ValueList, test.MinDefinite)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %s with quorum %d", v, test.Result, test.ValueList, test.MinDefinite)
+		}
+	}
+}
+
+func TestFromBoolErr(t *testing.T) {
+	r := FromBoolErr(false, nil)
+	if r != FALSE {
+		t.Errorf("ternary = %s, want %s for %t with no error", r, FALSE, false)
+	}
+
+	r = FromBoolErr(true, nil)
+	if r != TRUE {
+		t.Errorf("ternary = %s, want %s for %t with no error", r, TRUE, true)
+	}
+
+	r = FromBoolErr(true, errors.New("probe failed"))
+	if r != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for %t with an error", r, UNKNOWN, true)
+	}
+}
+
+var runLengthTests = []struct {
+	ValueList []Value
+	Runs      []Run
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE, TRUE},
+		Runs:      []Run{{V: TRUE, Count: 4}},
+	},
+	{
+		ValueList: []Value{TRUE, FALSE, TRUE, FALSE},
+		Runs:      []Run{{V: TRUE, Count: 1}, {V: FALSE, Count: 1}, {V: TRUE, Count: 1}, {V: FALSE, Count: 1}},
+	},
+	{
+		ValueList: []Value{UNKNOWN, UNKNOWN, TRUE, FALSE, FALSE, FALSE},
+		Runs:      []Run{{V: UNKNOWN, Count: 2}, {V: TRUE, Count: 1}, {V: FALSE, Count: 3}},
+	},
+	{
+		ValueList: []Value{},
+		Runs:      nil,
+	},
+}
+
+func TestRunLengthEncode(t *testing.T) {
+	for _, test := range runLengthTests {
+		runs := RunLengthEncode(test.ValueList)
+		if !reflect.DeepEqual(runs, test.Runs) {
+			t.Errorf("runs = %v, want %v for %s", runs, test.Runs, test.ValueList)
+		}
+	}
+}
+
+var runLengthDecodeErrorTests = []struct {
+	Runs []Run
+	Err  string
+}{
+	{
+		Runs: []Run{{V: TRUE, Count: -1}},
+		Err:  "decode run of TRUE with count -1: invalid value",
+	},
+	{
+		Runs: []Run{{V: TRUE, Count: 3}, {V: FALSE, Count: -2}, {V: UNKNOWN, Count: 1}},
+		Err:  "decode run of FALSE with count -2: invalid value",
+	},
+}
+
+func TestRunLengthDecode(t *testing.T) {
+	for _, test := range runLengthTests {
+		values, err := RunLengthDecode(test.Runs)
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(values, test.ValueList) {
+			t.Errorf("values = %s, want %s for %v", values, test.ValueList, test.Runs)
+		}
+
+		values, err = RunLengthDecode(RunLengthEncode(test.ValueList))
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(values, test.ValueList) {
+			t.Errorf("values = %s, want %s for round trip", values, test.ValueList)
+		}
+	}
+
+	for _, test := range runLengthDecodeErrorTests {
+		_, err := RunLengthDecode(test.Runs)
+		if err == nil {
+			t.Errorf("no error, want error %q for %v", test.Err, test.Runs)
+		} else if err.Error() != test.Err {
+			t.Errorf("error = %q, want error %q for %v", err.Error(), test.Err, test.Runs)
+		}
+	}
+}
+
+var agreementScoreTests = []struct {
+	Values1 []Value
+	Values2 []Value
+	Result  float64
+	Err     string
+}{
+	{
+		Values1: []Value{TRUE, UNKNOWN, FALSE},
+		Values2: []Value{TRUE, UNKNOWN, FALSE},
+		Result:  1,
+	},
+	{
+		Values1: []Value{TRUE, UNKNOWN, FALSE},
+		Values2: []Value{FALSE, TRUE, UNKNOWN},
+		Result:  0,
+	},
+	{
+		Values1: []Value{TRUE, UNKNOWN, FALSE, TRUE},
+		Values2: []Value{TRUE, FALSE, FALSE, UNKNOWN},
+		Result:  0.5,
+	},
+	{
+		Values1: []Value{},
+		Values2: []Value{},
+		Result:  math.NaN(),
+	},
+	{
+		Values1: []Value{TRUE, UNKNOWN},
+		Values2: []Value{TRUE},
+		Err:     "compare 2 and 1 values: length mismatch",
+	},
+}
+
+func TestAgreementScore(t *testing.T) {
+	for _, test := range agreementScoreTests {
+		f, err := AgreementScore(test.Values1, test.Values2)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s and %s", err.Error(), test.Err, test.Values1, test.Values2)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s and %s", test.Err, test.Values1, test.Values2)
+			continue
+		}
+		if math.IsNaN(test.Result) {
+			if !math.IsNaN(f) {
+				t.Errorf("score = %f, want NaN for %s and %s", f, test.Values1, test.Values2)
+			}
+			continue
+		}
+		if f != test.Result {
+			t.Errorf("score = %f, want %f for %s and %s", f, test.Result, test.Values1, test.Values2)
+		}
+	}
+}
+
+var cohenKappaTests = []struct {
+	Values1 []Value
+	Values2 []Value
+	Result  float64
+	Err     string
+}{
+	{
+		Values1: []Value{TRUE, TRUE, TRUE, FALSE, FALSE, UNKNOWN, UNKNOWN, TRUE},
+		Values2: []Value{TRUE, FALSE, TRUE, FALSE, UNKNOWN, UNKNOWN, TRUE, TRUE},
+		Result:  0.4,
+	},
+	{
+		Values1: []Value{TRUE, FALSE, UNKNOWN},
+		Values2: []Value{TRUE, FALSE, UNKNOWN},
+		Result:  1,
+	},
+	{
+		Values1: []Value{TRUE, FALSE},
+		Values2: []Value{FALSE, TRUE},
+		Result:  -1,
+	},
+	{
+		Values1: []Value{TRUE, TRUE, TRUE},
+		Values2: []Value{TRUE, TRUE, TRUE},
+		Result:  math.NaN(),
+	},
+	{
+		Values1: []Value{},
+		Values2: []Value{},
+		Result:  math.NaN(),
+	},
+	{
+		Values1: []Value{TRUE},
+		Values2: []Value{},
+		Err:     "compare 1 and 0 values: length mismatch",
+	},
+}
+
+func TestCohenKappa(t *testing.T) {
+	for _, test := range cohenKappaTests {
+		f, err := CohenKappa(test.Values1, test.Values2)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s and %s", err.Error(), test.Err, test.Values1, test.Values2)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s and %s", test.Err, test.Values1, test.Values2)
+			continue
+		}
+		if math.IsNaN(test.Result) {
+			if !math.IsNaN(f) {
+				t.Errorf("kappa = %f, want NaN for %s and %s", f, test.Values1, test.Values2)
+			}
+			continue
+		}
+		if f != test.Result {
+			t.Errorf("kappa = %f, want %f for %s and %s", f, test.Result, test.Values1, test.Values2)
+		}
+	}
+}
+
+var transitionMatrixTests = []struct {
+	Sequence []Value
+	Result   [3][3]int
+}{
+	{
+		Sequence: []Value{TRUE, TRUE, UNKNOWN, FALSE, TRUE, TRUE, FALSE},
+		Result: [3][3]int{
+			{0, 0, 1},
+			{1, 0, 0},
+			{1, 1, 2},
+		},
+	},
+	{
+		Sequence: []Value{UNKNOWN},
+		Result:   [3][3]int{},
+	},
+	{
+		Sequence: []Value{},
+		Result:   [3][3]int{},
+	},
+}
+
+func TestTransitionMatrix(t *testing.T) {
+	for _, test := range transitionMatrixTests {
+		m := TransitionMatrix(test.Sequence)
+		if m != test.Result {
+			t.Errorf("matrix = %v, want %v for %s", m, test.Result, test.Sequence)
+		}
+	}
+}
+
+var medianFilter3Tests = []struct {
+	ValueList []Value
+	Result    []Value
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, FALSE, TRUE, TRUE},
+		Result:    []Value{TRUE, TRUE, TRUE, TRUE, TRUE},
+	},
+	{
+		ValueList: []Value{FALSE, TRUE, TRUE, UNKNOWN, TRUE},
+		Result:    []Value{FALSE, TRUE, TRUE, TRUE, TRUE},
+	},
+	{
+		ValueList: []Value{FALSE, UNKNOWN, TRUE},
+		Result:    []Value{FALSE, UNKNOWN, TRUE},
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Result:    []Value{TRUE, FALSE},
+	},
+	{
+		ValueList: []Value{},
+		Result:    []Value{},
+	},
+}
+
+func TestMedianFilter3(t *testing.T) {
+	for _, test := range medianFilter3Tests {
+		v := MedianFilter3(test.ValueList)
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("values = %s, want %s for %s", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+var groupTests = []KeyValue{
+	{Key: "network", V: TRUE},
+	{Key: "storage", V: TRUE},
+	{Key: "network", V: UNKNOWN},
+	{Key: "auth", V: FALSE},
+	{Key: "storage", V: TRUE},
+	{Key: "auth", V: UNKNOWN},
+	{Key: "auth", V: TRUE},
+}
+
+func TestGroupAll(t *testing.T) {
+	expect := map[string]Value{
+		"network": UNKNOWN,
+		"storage": TRUE,
+		"auth":    FALSE,
+	}
+	result := GroupAll(groupTests)
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("result = %v, want %v", result, expect)
+	}
+}
+
+func TestGroupAny(t *testing.T) {
+	expect := map[string]Value{
+		"network": TRUE,
+		"storage": TRUE,
+		"auth":    TRUE,
+	}
+	result := GroupAny(groupTests)
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("result = %v, want %v", result, expect)
+	}
+
+	result = GroupAny(nil)
+	if len(result) != 0 {
+		t.Errorf("result = %v, want empty map", result)
+	}
+}
+
+func TestAtomic(t *testing.T) {
+	var a Atomic
+
+	v := a.Load()
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for zero value", v, UNKNOWN)
+	}
+
+	a.Store(TRUE)
+	v = a.Load()
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s after store", v, TRUE)
+	}
+
+	if a.CompareAndSwap(FALSE, UNKNOWN) {
+		t.Errorf("swapped, want no swap for compare with %s", FALSE)
+	}
+	if !a.CompareAndSwap(TRUE, FALSE) {
+		t.Errorf("not swapped, want swap for compare with %s", TRUE)
+	}
+	v = a.Load()
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s after swap", v, FALSE)
+	}
+
+	var wg sync.WaitGroup
+	swaps := make(chan bool, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			swaps <- a.CompareAndSwap(FALSE, TRUE)
+			_ = a.Load()
+		}()
+	}
+	wg.Wait()
+	close(swaps)
+
+	n := 0
+	for swapped := range swaps {
+		if swapped {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("swaps = %d, want %d for concurrent compare and swap", n, 1)
+	}
+}
+
+var andExplainTests = []struct {
+	ValueList []Value
+	Result    Value
+	Index     int
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE, FALSE},
+		Result:    FALSE,
+		Index:     2,
+	},
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE},
+		Result:    TRUE,
+		Index:     -1,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE, UNKNOWN},
+		Result:    UNKNOWN,
+		Index:     1,
+	},
+	{
+		ValueList: []Value{},
+		Result:    TRUE,
+		Index:     -1,
+	},
+}
+
+func TestAndExplain(t *testing.T) {
+	for _, test := range andExplainTests {
+		v, idx := AndExplain(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %s", v, test.Result, test.ValueList)
+		}
+		if idx != test.Index {
+			t.Errorf("index = %d, want %d for %s", idx, test.Index, test.ValueList)
+		}
+	}
+}
+
+var fromIntervalTests = []struct {
+	Lower  float64
+	Upper  float64
+	Result Value
+	Err    string
+}{
+	{
+		Lower:  0.2,
+		Upper:  0.8,
+		Result: TRUE,
+	},
+	{
+		Lower:  -0.9,
+		Upper:  -0.1,
+		Result: FALSE,
+	},
+	{
+		Lower:  -0.3,
+		Upper:  0.4,
+		Result: UNKNOWN,
+	},
+	{
+		Lower:  0,
+		Upper:  0.5,
+		Result: UNKNOWN,
+	},
+	{
+		Lower: 0.5,
+		Upper: 0.2,
+		Err:   "convert from [0.5, 0.2]: invalid interval",
+	},
+	{
+		Lower: -1.5,
+		Upper: 0.2,
+		Err:   "convert from [-1.5, 0.2]: invalid interval",
+	},
+	{
+		Lower: math.NaN(),
+		Upper: 0.2,
+		Err:   "convert from [NaN, 0.2]: invalid interval",
+	},
+}
+
+func TestFromInterval(t *testing.T) {
+	for _, test := range fromIntervalTests {
+		v, err := FromInterval(test.Lower, test.Upper)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for [%v, %v]", err.Error(), test.Err, test.Lower, test.Upper)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for [%v, %v]", test.Err, test.Lower, test.Upper)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for [%v, %v]", v, test.Result, test.Lower, test.Upper)
+		}
+	}
+}
+
+func TestValue_Update(t *testing.T) {
+	for _, test := range agreeOrUnknownTests {
+		v := test.Value1.Update(test.Value2)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for %s.Update(%s)", v, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+var mergeAllTests = []struct {
+	ValueList []Value
+	Result    Value
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    TRUE,
+	},
+	{
+		ValueList: []Value{UNKNOWN, FALSE, FALSE},
+		Result:    FALSE,
+	},
+	{
+		ValueList: []Value{TRUE, FALSE, TRUE},
+		Result:    UNKNOWN,
+	},
+	{
+		ValueList: []Value{UNKNOWN, UNKNOWN},
+		Result:    UNKNOWN,
+	},
+	{
+		ValueList: []Value{},
+		Result:    UNKNOWN,
+	},
+}
+
+func TestMergeAll(t *testing.T) {
+	for _, test := range mergeAllTests {
+		v := MergeAll(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for merge all \"%s\"", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+var matchesTests = []struct {
+	Value1 Value
+	Value2 Value
+	Result bool
+}{
+	{
+		Value1: FALSE,
+		Value2: FALSE,
+		Result: true,
+	},
+	{
+		Value1: FALSE,
+		Value2: UNKNOWN,
+		Result: true,
+	},
+	{
+		Value1: FALSE,
+		Value2: TRUE,
+		Result: false,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: FALSE,
+		Result: true,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: UNKNOWN,
+		Result: true,
+	},
+	{
+		Value1: UNKNOWN,
+		Value2: TRUE,
+		Result: true,
+	},
+	{
+		Value1: TRUE,
+		Value2: FALSE,
+		Result: false,
+	},
+	{
+		Value1: TRUE,
+		Value2: UNKNOWN,
+		Result: true,
+	},
+	{
+		Value1: TRUE,
+		Value2: TRUE,
+		Result: true,
+	},
+}
+
+func TestValue_Matches(t *testing.T) {
+	for _, test := range matchesTests {
+		b := test.Value1.Matches(test.Value2)
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for %s.Matches(%s)", b, test.Result, test.Value1, test.Value2)
+		}
+	}
+}
+
+func TestAssignments(t *testing.T) {
+	expect := 1
+	for n := 0; n <= 5; n++ {
+		assignments := Assignments(n)
+		if len(assignments) != expect {
+			t.Errorf("count = %d, want %d for %d variables", len(assignments), expect, n)
+		}
+		expect *= 3
+	}
+
+	assignments := Assignments(2)
+	first := [][]Value{
+		{FALSE, FALSE},
+		{FALSE, UNKNOWN},
+		{FALSE, TRUE},
+		{UNKNOWN, FALSE},
+	}
+	if !reflect.DeepEqual(assignments[:4], first) {
+		t.Errorf("assignments = %s, want %s for the first four", assignments[:4], first)
+	}
+	if !reflect.DeepEqual(assignments[8], []Value{TRUE, TRUE}) {
+		t.Errorf("assignment = %s, want %s for the last", assignments[8], []Value{TRUE, TRUE})
+	}
+
+	assignments = Assignments(-1)
+	if assignments != nil {
+		t.Errorf("assignments = %s, want nil for %d variables", assignments, -1)
+	}
+
+	assignments = Assignments(MaxAssignmentVariables + 1)
+	if assignments != nil {
+		t.Errorf("assignments = %s, want nil for %d variables", assignments, MaxAssignmentVariables+1)
+	}
+}
+
+var convertFromDualRailTests = []struct {
+	T      bool
+	F      bool
+	Result Value
+	Err    string
+}{
+	{
+		T:      false,
+		F:      false,
+		Result: UNKNOWN,
+	},
+	{
+		T:      false,
+		F:      true,
+		Result: FALSE,
+	},
+	{
+		T:      true,
+		F:      false,
+		Result: TRUE,
+	},
+	{
+		T:   true,
+		F:   true,
+		Err: "convert from dual rail (true, true): invalid value",
+	},
+}
+
+func TestConvertFromDualRail(t *testing.T) {
+	for _, test := range convertFromDualRailTests {
+		v, err := ConvertFromDualRail(test.T, test.F)
+		if err != nil {
+			var e *ConversionError
+			if !errors.As(err, &e) {
+				t.Errorf("error = %v, want *ConversionError for (%t, %t)", err, test.T, test.F)
+			}
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for (%t, %t)", err.Error(), test.Err, test.T, test.F)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for (%t, %t)", test.Err, test.T, test.F)
+			continue
+		}
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for (%t, %t)", v, test.Result, test.T, test.F)
+		}
+
+		rt, rf := v.DualRail()
+		if rt != test.T || rf != test.F {
+			t.Errorf("dual rail = (%t, %t), want (%t, %t) for %s", rt, rf, test.T, test.F, v)
+		}
+	}
+}
+
+func TestClassicalRestriction(t *testing.T) {
+	and, ok := ClassicalRestriction(And)
+	if !ok {
+		t.Errorf("classical = %t, want %t for and", ok, true)
+	}
+	for _, a := range []bool{false, true} {
+		for _, b := range []bool{false, true} {
+			if r := and(a, b); r != (a && b) {
+				t.Errorf("bool value = %t, want %t for \"%t and %t\"", r, a && b, a, b)
+			}
+		}
+	}
+
+	_, ok = ClassicalRestriction(func(a Value, b Value) Value {
+		if a != b {
+			return UNKNOWN
+		}
+		return a
+	})
+	if ok {
+		t.Errorf("classical = %t, want %t for an operator returning unknown", ok, false)
+	}
+}
+
+func TestValue_Next(t *testing.T) {
+	v := FALSE.Next()
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for %s.Next()", v, UNKNOWN, FALSE)
+	}
+
+	v = UNKNOWN.Next()
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s for %s.Next()", v, TRUE, UNKNOWN)
+	}
+
+	v = TRUE.Next()
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s for %s.Next()", v, TRUE, TRUE)
+	}
+}
+
+func TestValue_Prev(t *testing.T) {
+	v := FALSE.Prev()
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s for %s.Prev()", v, FALSE, FALSE)
+	}
+
+	v = UNKNOWN.Prev()
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s for %s.Prev()", v, FALSE, UNKNOWN)
+	}
+
+	v = TRUE.Prev()
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for %s.Prev()", v, UNKNOWN, TRUE)
+	}
+}
+
+var centroidTests = []struct {
+	ValueList []Value
+	Weights   []float64
+	Result    float64
+	Err       string
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, FALSE, UNKNOWN},
+		Weights:   []float64{1, 1, 1, 1},
+		Result:    0.25,
+	},
+	{
+		ValueList: []Value{TRUE, FALSE, UNKNOWN},
+		Weights:   []float64{3, 1, 0},
+		Result:    0.5,
+	},
+	{
+		ValueList: []Value{FALSE, UNKNOWN},
+		Weights:   []float64{1, 3},
+		Result:    -0.25,
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{1},
+		Err:       "weigh 2 values with 1 weights: length mismatch",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{0, 0},
+		Err:       "weigh values: total weight is zero",
+	},
+	{
+		ValueList: []Value{},
+		Weights:   []float64{},
+		Err:       "weigh values: total weight is zero",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{2, -1},
+		Err:       "weigh values with weight -1: negative weight",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{1, math.NaN()},
+		Err:       "weigh values with weight NaN: invalid weight",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{math.Inf(1), 1},
+		Err:       "weigh values with weight +Inf: invalid weight",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{1, math.Inf(-1)},
+		Err:       "weigh values with weight -Inf: invalid weight",
+	},
+	{
+		ValueList: []Value{TRUE, FALSE},
+		Weights:   []float64{math.MaxFloat64, math.MaxFloat64},
+		Err:       "weigh values: total weight overflows",
+	},
+}
+
+func TestCentroid(t *testing.T) {
+	for _, test := range centroidTests {
+		f, err := Centroid(test.ValueList, test.Weights)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s with weights %v", err.Error(), test.Err, test.ValueList, test.Weights)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s with weights %v", test.Err, test.ValueList, test.Weights)
+			continue
+		}
+		if f != test.Result {
+			t.Errorf("centroid = %f, want %f for %s with weights %v", f, test.Result, test.ValueList, test.Weights)
+		}
+	}
+}
+
+var flapCountTests = []struct {
+	ValueList []Value
+	Result    int
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE, TRUE},
+		Result:    0,
+	},
+	{
+		ValueList: []Value{TRUE, FALSE, TRUE, UNKNOWN, TRUE},
+		Result:    4,
+	},
+	{
+		ValueList: []Value{TRUE, TRUE, UNKNOWN, UNKNOWN, FALSE},
+		Result:    2,
+	},
+	{
+		ValueList: []Value{FALSE},
+		Result:    0,
+	},
+	{
+		ValueList: []Value{},
+		Result:    0,
+	},
+}
+
+func TestFlapCount(t *testing.T) {
+	for _, test := range flapCountTests {
+		n := FlapCount(test.ValueList)
+		if n != test.Result {
+			t.Errorf("count = %d, want %d for %s", n, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestIsFlapping(t *testing.T) {
+	values := []Value{TRUE, FALSE, TRUE, TRUE, FALSE}
+
+	b := IsFlapping(values, 3)
+	if b != true {
+		t.Errorf("bool value = %t, want %t for %s with threshold %d", b, true, values, 3)
+	}
+
+	b = IsFlapping(values, 4)
+	if b != false {
+		t.Errorf("bool value = %t, want %t for %s with threshold %d", b, false, values, 4)
+	}
+}
+
+var safeAllTests = []struct {
+	ValueList []Value
+	Result    Value
+	All       Value
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE},
+		Result:    TRUE,
+		All:       TRUE,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    FALSE,
+		All:       UNKNOWN,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE},
+		Result:    FALSE,
+		All:       FALSE,
+	},
+	{
+		ValueList: []Value{},
+		Result:    TRUE,
+		All:       TRUE,
+	},
+}
+
+func TestSafeAll(t *testing.T) {
+	for _, test := range safeAllTests {
+		v := SafeAll(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for safe all \"%s\"", v, test.Result, test.ValueList)
+		}
+		if a := All(test.ValueList); a != test.All {
+			t.Errorf("ternary = %s, want %s for all \"%s\"", a, test.All, test.ValueList)
+		}
+	}
+}
+
+var openAnyTests = []struct {
+	ValueList []Value
+	Result    Value
+	Any       Value
+}{
+	{
+		ValueList: []Value{FALSE, FALSE, FALSE},
+		Result:    FALSE,
+		Any:       FALSE,
+	},
+	{
+		ValueList: []Value{FALSE, UNKNOWN, FALSE},
+		Result:    TRUE,
+		Any:       UNKNOWN,
+	},
+	{
+		ValueList: []Value{FALSE, UNKNOWN, TRUE},
+		Result:    TRUE,
+		Any:       TRUE,
+	},
+	{
+		ValueList: []Value{},
+		Result:    FALSE,
+		Any:       FALSE,
+	},
+}
+
+func TestOpenAny(t *testing.T) {
+	for _, test := range openAnyTests {
+		v := OpenAny(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for open any \"%s\"", v, test.Result, test.ValueList)
+		}
+		if a := Any(test.ValueList); a != test.Any {
+			t.Errorf("ternary = %s, want %s for any \"%s\"", a, test.Any, test.ValueList)
+		}
+	}
+}
+
+func TestValue_WrapperBool(t *testing.T) {
+	b := UNKNOWN.WrapperBool()
+	if b != nil {
+		t.Errorf("pointer = %v, want nil for %s", *b, UNKNOWN)
+	}
+
+	b = FALSE.WrapperBool()
+	if b == nil || *b != false {
+		t.Errorf("pointer = %v, want pointer to %t for %s", b, false, FALSE)
+	}
+
+	b = TRUE.WrapperBool()
+	if b == nil || *b != true {
+		t.Errorf("pointer = %v, want pointer to %t for %s", b, true, TRUE)
+	}
+}
+
+func TestConvertFromWrapperBool(t *testing.T) {
+	r := ConvertFromWrapperBool(nil)
+	if r != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for nil", r, UNKNOWN)
+	}
+
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		r = ConvertFromWrapperBool(v.WrapperBool())
+		if r != v {
+			t.Errorf("ternary = %s, want %s for round trip", r, v)
+		}
+	}
+}
+
+var isCompleteTests = []struct {
+	ValueList []Value
+	Result    bool
+	Missing   []int
+}{
+	{
+		ValueList: []Value{TRUE, FALSE, TRUE},
+		Result:    true,
+		Missing:   nil,
+	},
+	{
+		ValueList: []Value{UNKNOWN, FALSE, TRUE, UNKNOWN},
+		Result:    false,
+		Missing:   []int{0, 3},
+	},
+	{
+		ValueList: []Value{},
+		Result:    true,
+		Missing:   nil,
+	},
+}
+
+func TestIsComplete(t *testing.T) {
+	for _, test := range isCompleteTests {
+		b := IsComplete(test.ValueList)
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for %s", b, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestMissingIndices(t *testing.T) {
+	for _, test := range isCompleteTests {
+		indices := MissingIndices(test.ValueList)
+		if !reflect.DeepEqual(indices, test.Missing) {
+			t.Errorf("indices = %v, want %v for %s", indices, test.Missing, test.ValueList)
+		}
+	}
+}
+
+var imputeFromNeighborsTests = []struct {
+	ValueList []Value
+	Result    []Value
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, UNKNOWN, UNKNOWN, FALSE},
+		Result:    []Value{TRUE, TRUE, TRUE, FALSE, FALSE},
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE},
+		Result:    []Value{TRUE, TRUE, FALSE},
+	},
+	{
+		ValueList: []Value{UNKNOWN, UNKNOWN, FALSE, TRUE, UNKNOWN},
+		Result:    []Value{FALSE, FALSE, FALSE, TRUE, TRUE},
+	},
+	{
+		ValueList: []Value{UNKNOWN, UNKNOWN, UNKNOWN},
+		Result:    []Value{UNKNOWN, UNKNOWN, UNKNOWN},
+	},
+	{
+		ValueList: []Value{},
+		Result:    []Value{},
+	},
+}
+
+func TestImputeFromNeighbors(t *testing.T) {
+	for _, test := range imputeFromNeighborsTests {
+		v := ImputeFromNeighbors(test.ValueList)
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("values = %s, want %s for %s", v, test.Result, test.ValueList)
+		}
+	}
+}
+
+var quorumTests = []struct {
+	N         int
+	ValueList []Value
+	AtLeast   Value
+	AtMost    Value
+	ExactlyN  Value
+}{
+	{
+		N:         1,
+		ValueList: []Value{UNKNOWN, FALSE},
+		AtLeast:   UNKNOWN,
+		AtMost:    TRUE,
+		ExactlyN:  UNKNOWN,
+	},
+	{
+		N:         1,
+		ValueList: []Value{TRUE, UNKNOWN},
+		AtLeast:   TRUE,
+		AtMost:    UNKNOWN,
+		ExactlyN:  UNKNOWN,
+	},
+	{
+		N:         2,
+		ValueList: []Value{TRUE, TRUE, FALSE},
+		AtLeast:   TRUE,
+		AtMost:    TRUE,
+		ExactlyN:  TRUE,
+	},
+	{
+		N:         2,
+		ValueList: []Value{TRUE, FALSE, FALSE},
+		AtLeast:   FALSE,
+		AtMost:    TRUE,
+		ExactlyN:  FALSE,
+	},
+	{
+		N:         1,
+		ValueList: []Value{TRUE, TRUE, UNKNOWN},
+		AtLeast:   TRUE,
+		AtMost:    FALSE,
+		ExactlyN:  FALSE,
+	},
+	{
+		N:         3,
+		ValueList: []Value{TRUE, UNKNOWN, FALSE},
+		AtLeast:   FALSE,
+		AtMost:    TRUE,
+		ExactlyN:  FALSE,
+	},
+	{
+		N:         0,
+		ValueList: []Value{UNKNOWN, UNKNOWN},
+		AtLeast:   TRUE,
+		AtMost:    UNKNOWN,
+		ExactlyN:  UNKNOWN,
+	},
+	{
+		N:         0,
+		ValueList: []Value{},
+		AtLeast:   TRUE,
+		AtMost:    TRUE,
+		ExactlyN:  TRUE,
+	},
+}
+
+func TestAtLeast(t *testing.T) {
+	for _, test := range quorumTests {
+		v := AtLeast(test.N, test.ValueList)
+		if v != test.AtLeast {
+			t.Errorf("ternary = %s, want %s for at least %d of %s", v, test.AtLeast, test.N, test.ValueList)
+		}
+	}
+}
+
+func TestAtMost(t *testing.T) {
+	for _, test := range quorumTests {
+		v := AtMost(test.N, test.ValueList)
+		if v != test.AtMost {
+			t.Errorf("ternary = %s, want %s for at most %d of %s", v, test.AtMost, test.N, test.ValueList)
+		}
+	}
+}
+
+func TestExactlyN(t *testing.T) {
+	for _, test := range quorumTests {
+		v := ExactlyN(test.N, test.ValueList)
+		if v != test.ExactlyN {
+			t.Errorf("ternary = %s, want %s for exactly %d of %s", v, test.ExactlyN, test.N, test.ValueList)
+		}
+	}
+}
+
+var countTests = []struct {
+	ValueList []Value
+	Trues     int
+	Unknowns  int
+	Falses    int
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE, TRUE, UNKNOWN, TRUE},
+		Trues:     3,
+		Unknowns:  2,
+		Falses:    1,
+	},
+	{
+		ValueList: []Value{FALSE, FALSE},
+		Trues:     0,
+		Unknowns:  0,
+		Falses:    2,
+	},
+	{
+		ValueList: []Value{},
+		Trues:     0,
+		Unknowns:  0,
+		Falses:    0,
+	},
+}
+
+func TestCount(t *testing.T) {
+	for _, test := range countTests {
+		trues, unknowns, falses := Count(test.ValueList)
+		if trues != test.Trues || unknowns != test.Unknowns || falses != test.Falses {
+			t.Errorf("counts = (%d, %d, %d), want (%d, %d, %d) for %s", trues, unknowns, falses, test.Trues, test.Unknowns, test.Falses, test.ValueList)
+		}
+	}
+}
+
+func TestAsymmetricPairs(t *testing.T) {
+	pairs := AsymmetricPairs(And)
+	if len(pairs) != 0 {
+		t.Errorf("pairs = %v, want no pairs for and", pairs)
+	}
+
+	expect := []Pair{
+		{A: FALSE, B: UNKNOWN},
+		{A: FALSE, B: TRUE},
+		{A: UNKNOWN, B: FALSE},
+		{A: UNKNOWN, B: TRUE},
+		{A: TRUE, B: FALSE},
+		{A: TRUE, B: UNKNOWN},
+	}
+	pairs = AsymmetricPairs(Imp)
+	if !reflect.DeepEqual(pairs, expect) {
+		t.Errorf("pairs = %v, want %v for imp", pairs, expect)
+	}
+}
+
+func TestCartesianApply(t *testing.T) {
+	a := []Value{TRUE, UNKNOWN}
+	b := []Value{FALSE, UNKNOWN, TRUE}
+
+	m := CartesianApply(a, b, Imp)
+	if len(m) != 2 {
+		t.Fatalf("rows = %d, want %d", len(m), 2)
+	}
+	for i, row := range m {
+		if len(row) != 3 {
+			t.Errorf("columns = %d, want %d for row %d", len(row), 3, i)
+		}
+	}
+	if m[0][0] != FALSE {
+		t.Errorf("ternary = %s, want %s at [0][0]", m[0][0], FALSE)
+	}
+	if m[1][2] != TRUE {
+		t.Errorf("ternary = %s, want %s at [1][2]", m[1][2], TRUE)
+	}
+	if m[1][0] != UNKNOWN {
+		t.Errorf("ternary = %s, want %s at [1][0]", m[1][0], UNKNOWN)
+	}
+
+	m = CartesianApply(nil, b, Imp)
+	if len(m) != 0 {
+		t.Errorf("rows = %d, want %d for empty slice", len(m), 0)
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	_, err := ConvertFromString("ParseError")
+	var e *ConversionError
+	if !errors.As(err, &e) {
+		t.Fatalf("error = %v, want *ConversionError for %q", err, "ParseError")
+	}
+	if e.Source != "ParseError" {
+		t.Errorf("source = %v, want %q", e.Source, "ParseError")
+	}
+
+	_, err = ConvertFromInt64(12345)
+	if !errors.As(err, &e) {
+		t.Fatalf("error = %v, want *ConversionError for %d", err, 12345)
+	}
+	if e.Source != int64(12345) {
+		t.Errorf("source = %v, want %d", e.Source, 12345)
 	}
 
-	i = TRUE.Int()
-	if i != 1 {
-		t.Errorf("int value = %d, want %d for %s", i, 1, TRUE)
+	var v Value
+	err = v.UnmarshalJSON([]byte("maybe"))
+	if !errors.As(err, &e) {
+		t.Fatalf("error = %v, want *ConversionError for %s", err, "maybe")
+	}
+	if e.Source != json.Number("maybe") {
+		t.Errorf("source = %v, want %s", e.Source, "maybe")
+	}
+
+	err = json.Unmarshal([]byte("2.0"), &v)
+	if !errors.As(err, &e) {
+		t.Fatalf("error = %v, want *ConversionError for %s", err, "2.0")
+	}
+	if e.Source != float64(2) {
+		t.Errorf("source = %v, want %v", e.Source, 2)
 	}
 }
 
-func TestValue_ParseBool(t *testing.T) {
-	b := FALSE.ParseBool()
-	if b != false {
-		t.Errorf("bool value = %t, want %t for %s", b, false, FALSE)
+var unanimousTests = []struct {
+	ValueList []Value
+	Result    Value
+}{
+	{
+		ValueList: []Value{TRUE, TRUE, TRUE},
+		Result:    TRUE,
+	},
+	{
+		ValueList: []Value{TRUE, FALSE, TRUE},
+		Result:    FALSE,
+	},
+	{
+		ValueList: []Value{UNKNOWN, TRUE, FALSE},
+		Result:    FALSE,
+	},
+	{
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    UNKNOWN,
+	},
+	{
+		ValueList: []Value{},
+		Result:    TRUE,
+	},
+}
+
+func TestUnanimous(t *testing.T) {
+	for _, test := range unanimousTests {
+		v := Unanimous(test.ValueList)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for unanimous \"%s\"", v, test.Result, test.ValueList)
+		}
 	}
 
-	b = UNKNOWN.ParseBool()
-	if b != false {
-		t.Errorf("bool value = %t, want %t for %s", b, false, UNKNOWN)
+	for _, values := range Assignments(3) {
+		if v, a := Unanimous(values), All(values); v != a {
+			t.Errorf("ternary = %s, want %s as all for unanimous \"%s\"", v, a, values)
+		}
 	}
+}
 
-	b = TRUE.ParseBool()
-	if b != true {
-		t.Errorf("bool value = %t, want %t for %s", b, true, TRUE)
+var consensusColumnsTests = []struct {
+	Sequences [][]Value
+	Result    []Value
+	Err       string
+}{
+	{
+		Sequences: [][]Value{
+			{TRUE, FALSE, TRUE, UNKNOWN},
+			{TRUE, FALSE, FALSE, UNKNOWN},
+			{FALSE, TRUE, UNKNOWN, TRUE},
+		},
+		Result: []Value{TRUE, FALSE, UNKNOWN, UNKNOWN},
+	},
+	{
+		Sequences: [][]Value{
+			{TRUE, FALSE},
+			{FALSE, TRUE},
+		},
+		Result: []Value{UNKNOWN, UNKNOWN},
+	},
+	{
+		Sequences: [][]Value{},
+		Result:    []Value{},
+	},
+	{
+		Sequences: [][]Value{
+			{TRUE, FALSE},
+			{TRUE, FALSE},
+			{TRUE},
+		},
+		Err: "combine sequences of 2 and 1 values: length mismatch",
+	},
+}
+
+func TestConsensusColumns(t *testing.T) {
+	for _, test := range consensusColumnsTests {
+		v, err := ConsensusColumns(test.Sequences)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s", err.Error(), test.Err, test.Sequences)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s", test.Err, test.Sequences)
+			continue
+		}
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("values = %s, want %s for %s", v, test.Result, test.Sequences)
+		}
 	}
 }
 
-var convertFromStringTests = []struct {
+var convertFromGraphQLEnumTests = []struct {
 	Str    string
 	Result Value
 	Err    string
 }{
 	{
-		Str:    "false",
+		Str:    "FALSE",
 		Result: FALSE,
 	},
 	{
-		Str:    "unknown",
+		Str:    "UNKNOWN",
 		Result: UNKNOWN,
 	},
 	{
-		Str:    "true",
-		Result: TRUE,
-	},
-	{
-		Str:    "-1",
-		Result: FALSE,
-	},
-	{
-		Str:    "0",
+		Str:    "MAYBE",
 		Result: UNKNOWN,
 	},
 	{
-		Str:    "1",
+		Str:    "TRUE",
 		Result: TRUE,
 	},
 	{
-		Str: "ParseError",
-		Err: "convert from \"ParseError\": invalid value",
+		Str: "true",
+		Err: "convert from \"true\": invalid value",
+	},
+	{
+		Str: "YES",
+		Err: "convert from \"YES\": invalid value",
 	},
 }
 
-func TestConvertFromString(t *testing.T) {
-	for _, test := range convertFromStringTests {
-		v, err := ConvertFromString(test.Str)
+func TestConvertFromGraphQLEnum(t *testing.T) {
+	for _, test := range convertFromGraphQLEnumTests {
+		v, err := ConvertFromGraphQLEnum(test.Str)
 		if err != nil {
 			if len(test.Err) < 1 {
 				t.Errorf("unexpected error: %q", err.Error())
 			} else if err.Error() != test.Err {
-				t.Errorf("error = %q, want error %q for %s", err.Error(), test.Err, test.Str)
+				t.Errorf("error = %q, want error %q for %q", err.Error(), test.Err, test.Str)
 			}
 			continue
 		}
 		if 0 < len(test.Err) {
-			t.Errorf("no error, want error %q for %s", test.Err, test.Str)
+			t.Errorf("no error, want error %q for %q", test.Err, test.Str)
 			continue
 		}
 		if v != test.Result {
@@ -111,352 +4375,895 @@ func TestConvertFromString(t *testing.T) {
 	}
 }
 
-var convertFromInt64Tests = []struct {
-	Int    int64
-	Result Value
-	Err    string
+func TestValue_GraphQLEnum(t *testing.T) {
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		s := v.GraphQLEnum()
+		if s != v.String() {
+			t.Errorf("enum = %q, want %q for %s", s, v.String(), v)
+		}
+		if r, _ := ConvertFromGraphQLEnum(s); r != v {
+			t.Errorf("ternary = %s, want %s for round trip", r, v)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := []Value{TRUE, UNKNOWN, FALSE, TRUE, FALSE}
+	b := []Value{TRUE, UNKNOWN, FALSE, TRUE, FALSE}
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Errorf("fingerprints differ, want equal for %s and %s", a, b)
+	}
+
+	different := [][]Value{
+		{TRUE, UNKNOWN, FALSE, TRUE, TRUE},
+		{FALSE, UNKNOWN, TRUE, TRUE, TRUE},
+		{TRUE, UNKNOWN, FALSE, TRUE},
+		{TRUE, UNKNOWN, FALSE, TRUE, FALSE, FALSE},
+		{},
+	}
+	for _, d := range different {
+		if Fingerprint(a) == Fingerprint(d) {
+			t.Errorf("fingerprints are equal, want different for %s and %s", a, d)
+		}
+	}
+
+	if Fingerprint([]Value{TRUE, FALSE}) == Fingerprint([]Value{FALSE, TRUE}) {
+		t.Errorf("fingerprints are equal, want different for reordered values")
+	}
+}
+
+func TestSolveOperator(t *testing.T) {
+	op, err := SolveOperator([]Constraint{
+		{A: TRUE, B: TRUE, Result: TRUE},
+		{A: TRUE, B: FALSE, Result: FALSE},
+		{A: FALSE, B: TRUE, Result: FALSE},
+		{A: FALSE, B: FALSE, Result: FALSE},
+		{A: TRUE, B: TRUE, Result: TRUE},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+	for a := FALSE; a <= TRUE; a++ {
+		for b := FALSE; b <= TRUE; b++ {
+			expect := UNKNOWN
+			if a != UNKNOWN && b != UNKNOWN {
+				expect = And(a, b)
+			}
+			if v := op(a, b); v != expect {
+				t.Errorf("ternary = %s, want %s for \"%s op %s\"", v, expect, a, b)
+			}
+		}
+	}
+
+	_, err = SolveOperator([]Constraint{
+		{A: TRUE, B: UNKNOWN, Result: UNKNOWN},
+		{A: TRUE, B: UNKNOWN, Result: TRUE},
+	})
+	expectErr := "solve operator at (TRUE, UNKNOWN): contradictory results UNKNOWN and TRUE"
+	if err == nil {
+		t.Errorf("no error, want error %q", expectErr)
+	} else if err.Error() != expectErr {
+		t.Errorf("error = %q, want error %q", err.Error(), expectErr)
+	}
+}
+
+var nullBoolTests = []struct {
+	Value    Value
+	NullBool sql.NullBool
 }{
 	{
-		Int:    -1,
-		Result: FALSE,
+		Value:    FALSE,
+		NullBool: sql.NullBool{Bool: false, Valid: true},
 	},
 	{
-		Int:    0,
-		Result: UNKNOWN,
+		Value:    UNKNOWN,
+		NullBool: sql.NullBool{Valid: false},
 	},
 	{
-		Int:    1,
-		Result: TRUE,
+		Value:    TRUE,
+		NullBool: sql.NullBool{Bool: true, Valid: true},
 	},
+}
+
+func TestValue_NullBool(t *testing.T) {
+	for _, test := range nullBoolTests {
+		nb := test.Value.NullBool()
+		if nb != test.NullBool {
+			t.Errorf("null bool = %v, want %v for %s", nb, test.NullBool, test.Value)
+		}
+	}
+}
+
+func TestConvertFromNullBool(t *testing.T) {
+	for _, test := range nullBoolTests {
+		v := ConvertFromNullBool(test.NullBool)
+		if v != test.Value {
+			t.Errorf("ternary = %s, want %s for %v", v, test.Value, test.NullBool)
+		}
+		if r := ConvertFromNullBool(v.NullBool()); r != v {
+			t.Errorf("ternary = %s, want %s for round trip", r, v)
+		}
+	}
+
+	v := ConvertFromNullBool(sql.NullBool{Bool: true, Valid: false})
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for NULL with a true bool", v, UNKNOWN)
+	}
+}
+
+var clampFloatTests = []struct {
+	X        float64
+	FalseMax float64
+	TrueMin  float64
+	Result   Value
+	Err      string
+}{
 	{
-		Int: 12345,
-		Err: "convert from 12345: invalid value",
+		X:        -0.5,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   FALSE,
+	},
+	{
+		X:        -0.2,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   FALSE,
+	},
+	{
+		X:        -0.19,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   UNKNOWN,
+	},
+	{
+		X:        0.19,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   UNKNOWN,
+	},
+	{
+		X:        0.2,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   TRUE,
+	},
+	{
+		X:        0.5,
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Result:   TRUE,
+	},
+	{
+		X:        0,
+		FalseMax: 0.2,
+		TrueMin:  0.2,
+		Err:      "clamp with bands (0.2, 0.2): overlapping bands",
+	},
+	{
+		X:        0,
+		FalseMax: 0.5,
+		TrueMin:  0.2,
+		Err:      "clamp with bands (0.5, 0.2): overlapping bands",
+	},
+	{
+		X:        math.NaN(),
+		FalseMax: -0.2,
+		TrueMin:  0.2,
+		Err:      "convert from NaN: invalid value",
 	},
 }
 
-func TestConvertFromInt64(t *testing.T) {
-	for _, test := range convertFromInt64Tests {
-		v, err := ConvertFromInt64(test.Int)
+func TestClampFloat(t *testing.T) {
+	for _, test := range clampFloatTests {
+		v, err := ClampFloat(test.X, test.FalseMax, test.TrueMin)
 		if err != nil {
 			if len(test.Err) < 1 {
 				t.Errorf("unexpected error: %q", err.Error())
 			} else if err.Error() != test.Err {
-				t.Errorf("error = %q, want error %q for %d", err.Error(), test.Err, test.Int)
+				t.Errorf("error = %q, want error %q for %v with bands (%v, %v)", err.Error(), test.Err, test.X, test.FalseMax, test.TrueMin)
 			}
 			continue
 		}
 		if 0 < len(test.Err) {
-			t.Errorf("no error, want error %q for %d", test.Err, test.Int)
+			t.Errorf("no error, want error %q for %v with bands (%v, %v)", test.Err, test.X, test.FalseMax, test.TrueMin)
 			continue
 		}
 		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for %d", v, test.Result, test.Int)
+			t.Errorf("ternary = %s, want %s for %v with bands (%v, %v)", v, test.Result, test.X, test.FalseMax, test.TrueMin)
 		}
 	}
 }
 
-func TestConvertFromBool(t *testing.T) {
-	r := ConvertFromBool(false)
+func TestValue_BoolPtr(t *testing.T) {
+	b := UNKNOWN.BoolPtr()
+	if b != nil {
+		t.Errorf("pointer = %v, want nil for %s", *b, UNKNOWN)
+	}
+
+	b = FALSE.BoolPtr()
+	if b == nil || *b != false {
+		t.Errorf("pointer = %v, want pointer to %t for %s", b, false, FALSE)
+	}
+
+	b = TRUE.BoolPtr()
+	if b == nil || *b != true {
+		t.Errorf("pointer = %v, want pointer to %t for %s", b, true, TRUE)
+	}
+}
+
+func TestConvertFromBoolPtr(t *testing.T) {
+	r := ConvertFromBoolPtr(nil)
+	if r != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for nil", r, UNKNOWN)
+	}
+
+	f := false
+	r = ConvertFromBoolPtr(&f)
 	if r != FALSE {
-		t.Errorf("ternary = %s, want %s for %t", r, FALSE, false)
+		t.Errorf("ternary = %s, want %s for pointer to %t", r, FALSE, f)
 	}
 
-	r = ConvertFromBool(true)
+	tr := true
+	r = ConvertFromBoolPtr(&tr)
 	if r != TRUE {
-		t.Errorf("ternary = %s, want %s for %t", r, TRUE, true)
+		t.Errorf("ternary = %s, want %s for pointer to %t", r, TRUE, tr)
+	}
+
+	for _, v := range []Value{FALSE, UNKNOWN, TRUE} {
+		r = ConvertFromBoolPtr(v.BoolPtr())
+		if r != v {
+			t.Errorf("ternary = %s, want %s for round trip", r, v)
+		}
 	}
 }
 
-var equalTests = []struct {
-	Value1 Value
-	Value2 Value
-	Result Value
+var fromFreshnessTests = []struct {
+	Age        time.Duration
+	WarnAfter  time.Duration
+	StaleAfter time.Duration
+	Result     Value
+	Err        string
 }{
 	{
-		Value1: FALSE,
-		Value2: FALSE,
-		Result: TRUE,
+		Age:        30 * time.Second,
+		WarnAfter:  time.Minute,
+		StaleAfter: 5 * time.Minute,
+		Result:     TRUE,
 	},
 	{
-		Value1: FALSE,
-		Value2: UNKNOWN,
-		Result: FALSE,
+		Age:        time.Minute,
+		WarnAfter:  time.Minute,
+		StaleAfter: 5 * time.Minute,
+		Result:     UNKNOWN,
+	},
+	{
+		Age:        3 * time.Minute,
+		WarnAfter:  time.Minute,
+		StaleAfter: 5 * time.Minute,
+		Result:     UNKNOWN,
+	},
+	{
+		Age:        5 * time.Minute,
+		WarnAfter:  time.Minute,
+		StaleAfter: 5 * time.Minute,
+		Result:     FALSE,
+	},
+	{
+		Age:        time.Hour,
+		WarnAfter:  time.Minute,
+		StaleAfter: 5 * time.Minute,
+		Result:     FALSE,
+	},
+	{
+		Age:        time.Minute,
+		WarnAfter:  time.Minute,
+		StaleAfter: time.Minute,
+		Result:     FALSE,
+	},
+	{
+		Age:        time.Minute,
+		WarnAfter:  5 * time.Minute,
+		StaleAfter: time.Minute,
+		Err:        "check freshness with thresholds (5m0s, 1m0s): invalid thresholds",
 	},
 }
 
-func TestEqual(t *testing.T) {
-	for _, test := range equalTests {
-		v := Equal(test.Value1, test.Value2)
+func TestFromFreshness(t *testing.T) {
+	for _, test := range fromFreshnessTests {
+		v, err := FromFreshness(test.Age, test.WarnAfter, test.StaleAfter)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s with thresholds (%s, %s)", err.Error(), test.Err, test.Age, test.WarnAfter, test.StaleAfter)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s with thresholds (%s, %s)", test.Err, test.Age, test.WarnAfter, test.StaleAfter)
+			continue
+		}
 		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"equal(%s, %s)\"", v, test.Result, test.Value1, test.Value2)
+			t.Errorf("ternary = %s, want %s for %s with thresholds (%s, %s)", v, test.Result, test.Age, test.WarnAfter, test.StaleAfter)
+		}
+	}
+}
+
+func TestUnknownCells(t *testing.T) {
+	expect := []Pair{
+		{A: UNKNOWN, B: UNKNOWN},
+		{A: UNKNOWN, B: TRUE},
+		{A: TRUE, B: UNKNOWN},
+	}
+	pairs := UnknownCells(And)
+	if !reflect.DeepEqual(pairs, expect) {
+		t.Errorf("pairs = %v, want %v for and", pairs, expect)
+	}
+
+	expect = []Pair{
+		{A: FALSE, B: UNKNOWN},
+		{A: UNKNOWN, B: FALSE},
+		{A: UNKNOWN, B: UNKNOWN},
+		{A: UNKNOWN, B: TRUE},
+		{A: TRUE, B: UNKNOWN},
+	}
+	pairs = UnknownCells(Eqv)
+	if !reflect.DeepEqual(pairs, expect) {
+		t.Errorf("pairs = %v, want %v for eqv", pairs, expect)
+	}
+
+	pairs = UnknownCells(Compare)
+	expect = []Pair{
+		{A: FALSE, B: FALSE},
+		{A: UNKNOWN, B: UNKNOWN},
+		{A: TRUE, B: TRUE},
+	}
+	if !reflect.DeepEqual(pairs, expect) {
+		t.Errorf("pairs = %v, want %v for compare", pairs, expect)
+	}
+}
+
+var sortTests = []struct {
+	ValueList []Value
+	Result    []Value
+}{
+	{
+		ValueList: []Value{TRUE, UNKNOWN, FALSE, TRUE, FALSE},
+		Result:    []Value{FALSE, FALSE, UNKNOWN, TRUE, TRUE},
+	},
+	{
+		ValueList: []Value{UNKNOWN},
+		Result:    []Value{UNKNOWN},
+	},
+	{
+		ValueList: []Value{},
+		Result:    []Value{},
+	},
+}
+
+func TestSort(t *testing.T) {
+	for _, test := range sortTests {
+		values := make([]Value, len(test.ValueList))
+		copy(values, test.ValueList)
+		Sort(values)
+		if !reflect.DeepEqual(values, test.Result) {
+			t.Errorf("values = %s, want %s for sort %s", values, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	for _, test := range sortTests {
+		values := make([]Value, len(test.ValueList))
+		copy(values, test.ValueList)
+		SortStable(values)
+		if !reflect.DeepEqual(values, test.Result) {
+			t.Errorf("values = %s, want %s for stable sort %s", values, test.Result, test.ValueList)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	for _, test := range allTests {
+		v := Reduce(test.ValueList, And, TRUE)
+		if v != test.Result {
+			t.Errorf("ternary = %s, want %s for reduce \"%s\" with and", v, test.Result, test.ValueList)
 		}
 	}
+
+	values := []Value{TRUE, TRUE, TRUE}
+	v := Reduce(values, Xor, FALSE)
+	if v != TRUE {
+		t.Errorf("ternary = %s, want %s for reduce \"%s\" with xor", v, TRUE, values)
+	}
+
+	values = []Value{TRUE, FALSE, TRUE}
+	v = Reduce(values, Xor, FALSE)
+	if v != FALSE {
+		t.Errorf("ternary = %s, want %s for reduce \"%s\" with xor", v, FALSE, values)
+	}
+
+	values = []Value{TRUE, UNKNOWN, TRUE}
+	v = Reduce(values, Xor, FALSE)
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for reduce \"%s\" with xor", v, UNKNOWN, values)
+	}
+
+	v = Reduce(nil, Imp, UNKNOWN)
+	if v != UNKNOWN {
+		t.Errorf("ternary = %s, want %s for reduce empty values", v, UNKNOWN)
+	}
 }
 
-var notTests = []struct {
-	Value  Value
-	Result Value
+var formulasEquivalentTests = []struct {
+	Name   string
+	Vars   int
+	F      func([]Value) Value
+	G      func([]Value) Value
+	Result bool
+	Err    string
 }{
 	{
-		Value:  FALSE,
-		Result: TRUE,
+		Name:   "De Morgan's law",
+		Vars:   2,
+		F:      func(v []Value) Value { return Not(And(v[0], v[1])) },
+		G:      func(v []Value) Value { return Or(Not(v[0]), Not(v[1])) },
+		Result: true,
 	},
 	{
-		Value:  TRUE,
-		Result: FALSE,
+		Name:   "the law of excluded middle and TRUE",
+		Vars:   1,
+		F:      func(v []Value) Value { return Or(v[0], Not(v[0])) },
+		G:      func(v []Value) Value { return TRUE },
+		Result: false,
 	},
 	{
-		Value:  UNKNOWN,
-		Result: UNKNOWN,
+		Name:   "negative variables",
+		Vars:   -1,
+		F:      func(v []Value) Value { return TRUE },
+		G:      func(v []Value) Value { return TRUE },
+		Result: false,
+		Err:    "compare formulas of -1 variables: number of variables out of range",
+	},
+	{
+		Name:   "too many variables",
+		Vars:   MaxAssignmentVariables + 1,
+		F:      func(v []Value) Value { return TRUE },
+		G:      func(v []Value) Value { return TRUE },
+		Result: false,
+		Err:    "compare formulas of 13 variables: number of variables out of range",
 	},
 }
 
-func TestNot(t *testing.T) {
-	for _, test := range notTests {
-		v := Not(test.Value)
-		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"not %s\"", v, test.Result, test.Value)
+func TestFormulasEquivalent(t *testing.T) {
+	for _, test := range formulasEquivalentTests {
+		b, err := FormulasEquivalent(test.Vars, test.F, test.G)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %s", err.Error(), test.Err, test.Name)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %s", test.Err, test.Name)
+			continue
+		}
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for %s", b, test.Result, test.Name)
 		}
 	}
 }
 
-var andTests = []struct {
-	Value1 Value
-	Value2 Value
-	Result Value
+func TestMap(t *testing.T) {
+	values := []Value{TRUE, UNKNOWN, FALSE}
+	result := Map(values, Not)
+	expect := []Value{FALSE, UNKNOWN, TRUE}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("values = %s, want %s for map not %s", result, expect, values)
+	}
+
+	result = Map([]Value{}, Not)
+	if result == nil || len(result) != 0 {
+		t.Errorf("values = %#v, want non-nil empty slice for empty input", result)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	values := []Value{TRUE, UNKNOWN, FALSE, UNKNOWN, TRUE}
+	result := Filter(values, func(v Value) bool { return v != UNKNOWN })
+	expect := []Value{TRUE, FALSE, TRUE}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("values = %s, want %s for filter %s", result, expect, values)
+	}
+
+	result = Filter(nil, func(v Value) bool { return true })
+	if result == nil || len(result) != 0 {
+		t.Errorf("values = %#v, want non-nil empty slice for empty input", result)
+	}
+}
+
+var hardenTests = []struct {
+	Value      Value
+	PreferTrue bool
+	Result     bool
 }{
 	{
-		Value1: FALSE,
-		Value2: FALSE,
-		Result: FALSE,
+		Value:      FALSE,
+		PreferTrue: false,
+		Result:     false,
 	},
 	{
-		Value1: FALSE,
-		Value2: UNKNOWN,
-		Result: FALSE,
+		Value:      FALSE,
+		PreferTrue: true,
+		Result:     false,
 	},
 	{
-		Value1: FALSE,
-		Value2: TRUE,
-		Result: FALSE,
+		Value:      UNKNOWN,
+		PreferTrue: false,
+		Result:     false,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: FALSE,
-		Result: FALSE,
+		Value:      UNKNOWN,
+		PreferTrue: true,
+		Result:     true,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: UNKNOWN,
-		Result: UNKNOWN,
+		Value:      TRUE,
+		PreferTrue: false,
+		Result:     true,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: TRUE,
-		Result: UNKNOWN,
+		Value:      TRUE,
+		PreferTrue: true,
+		Result:     true,
 	},
+}
+
+func TestHarden(t *testing.T) {
+	for _, test := range hardenTests {
+		b := Harden(test.Value, test.PreferTrue)
+		if b != test.Result {
+			t.Errorf("bool value = %t, want %t for %s preferring %t", b, test.Result, test.Value, test.PreferTrue)
+		}
+	}
+}
+
+var pairwiseApplyTests = []struct {
+	ValueList []Value
+	Result    []Value
+}{
 	{
-		Value1: TRUE,
-		Value2: FALSE,
-		Result: FALSE,
+		ValueList: []Value{TRUE, TRUE, FALSE, FALSE, UNKNOWN},
+		Result:    []Value{TRUE, FALSE, TRUE, UNKNOWN},
 	},
 	{
-		Value1: TRUE,
-		Value2: UNKNOWN,
-		Result: UNKNOWN,
+		ValueList: []Value{TRUE},
+		Result:    []Value{},
 	},
 	{
-		Value1: TRUE,
-		Value2: TRUE,
-		Result: TRUE,
+		ValueList: []Value{},
+		Result:    []Value{},
 	},
 }
 
-func TestAnd(t *testing.T) {
-	for _, test := range andTests {
-		v := And(test.Value1, test.Value2)
-		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"%s and %s\"", v, test.Result, test.Value1, test.Value2)
+func TestPairwiseApply(t *testing.T) {
+	for _, test := range pairwiseApplyTests {
+		v := PairwiseApply(test.ValueList, Eqv)
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("values = %s, want %s for %s", v, test.Result, test.ValueList)
 		}
 	}
 }
 
-var orTests = []struct {
-	Value1 Value
-	Value2 Value
-	Result Value
+type quantifierRecord struct {
+	Name   string
+	Active Value
+}
+
+var quantifierFuncTests = []struct {
+	Items     []quantifierRecord
+	AllResult Value
+	AnyResult Value
+	AllCalls  int
+	AnyCalls  int
 }{
 	{
-		Value1: FALSE,
-		Value2: FALSE,
-		Result: FALSE,
+		Items:     []quantifierRecord{{Name: "a", Active: TRUE}, {Name: "b", Active: UNKNOWN}, {Name: "c", Active: TRUE}},
+		AllResult: UNKNOWN,
+		AnyResult: TRUE,
+		AllCalls:  3,
+		AnyCalls:  1,
 	},
 	{
-		Value1: FALSE,
-		Value2: UNKNOWN,
-		Result: UNKNOWN,
+		Items:     []quantifierRecord{{Name: "a", Active: UNKNOWN}, {Name: "b", Active: FALSE}, {Name: "c", Active: TRUE}},
+		AllResult: FALSE,
+		AnyResult: TRUE,
+		AllCalls:  2,
+		AnyCalls:  3,
 	},
 	{
-		Value1: FALSE,
-		Value2: TRUE,
-		Result: TRUE,
+		Items:     []quantifierRecord{{Name: "a", Active: FALSE}, {Name: "b", Active: UNKNOWN}},
+		AllResult: FALSE,
+		AnyResult: UNKNOWN,
+		AllCalls:  1,
+		AnyCalls:  2,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: FALSE,
-		Result: UNKNOWN,
+		Items:     []quantifierRecord{},
+		AllResult: TRUE,
+		AnyResult: FALSE,
+		AllCalls:  0,
+		AnyCalls:  0,
 	},
+}
+
+func TestAllFunc(t *testing.T) {
+	for _, test := range quantifierFuncTests {
+		calls := 0
+		pred := func(r quantifierRecord) Value {
+			calls++
+			return r.Active
+		}
+
+		v := AllFunc(test.Items, pred)
+		if v != test.AllResult {
+			t.Errorf("ternary = %s, want %s for %v", v, test.AllResult, test.Items)
+		}
+		if calls != test.AllCalls {
+			t.Errorf("calls = %d, want %d for %v", calls, test.AllCalls, test.Items)
+		}
+	}
+}
+
+func TestAnyFunc(t *testing.T) {
+	for _, test := range quantifierFuncTests {
+		calls := 0
+		pred := func(r quantifierRecord) Value {
+			calls++
+			return r.Active
+		}
+
+		v := AnyFunc(test.Items, pred)
+		if v != test.AnyResult {
+			t.Errorf("ternary = %s, want %s for %v", v, test.AnyResult, test.Items)
+		}
+		if calls != test.AnyCalls {
+			t.Errorf("calls = %d, want %d for %v", calls, test.AnyCalls, test.Items)
+		}
+	}
+}
+
+var majorityWithVetoTests = []struct {
+	ValueList []Value
+	Result    Value
+}{
 	{
-		Value1: UNKNOWN,
-		Value2: UNKNOWN,
-		Result: UNKNOWN,
+		ValueList: []Value{TRUE, TRUE, FALSE, TRUE},
+		Result:    FALSE,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: TRUE,
-		Result: TRUE,
+		ValueList: []Value{TRUE, UNKNOWN, TRUE},
+		Result:    TRUE,
 	},
 	{
-		Value1: TRUE,
-		Value2: FALSE,
-		Result: TRUE,
+		ValueList: []Value{UNKNOWN, TRUE, UNKNOWN},
+		Result:    UNKNOWN,
 	},
 	{
-		Value1: TRUE,
-		Value2: UNKNOWN,
-		Result: TRUE,
+		ValueList: []Value{TRUE, UNKNOWN},
+		Result:    UNKNOWN,
 	},
 	{
-		Value1: TRUE,
-		Value2: TRUE,
-		Result: TRUE,
+		ValueList: []Value{},
+		Result:    UNKNOWN,
 	},
 }
 
-func TestOr(t *testing.T) {
-	for _, test := range orTests {
-		v := Or(test.Value1, test.Value2)
+func TestMajorityWithVeto(t *testing.T) {
+	for _, test := range majorityWithVetoTests {
+		v := MajorityWithVeto(test.ValueList)
 		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"%s or %s\"", v, test.Result, test.Value1, test.Value2)
+			t.Errorf("ternary = %s, want %s for %s", v, test.Result, test.ValueList)
 		}
 	}
 }
 
-var impTests = []struct {
-	Value1 Value
-	Value2 Value
+var andThenTests = []struct {
+	A      Value
+	B      Value
 	Result Value
+	Called bool
 }{
 	{
-		Value1: TRUE,
-		Value2: FALSE,
+		A:      FALSE,
+		B:      TRUE,
 		Result: FALSE,
+		Called: false,
 	},
 	{
-		Value1: UNKNOWN,
-		Value2: UNKNOWN,
-		Result: UNKNOWN,
-	},
-}
-
-func TestImp(t *testing.T) {
-	for _, test := range impTests {
-		v := Imp(test.Value1, test.Value2)
-		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"%s imp %s\"", v, test.Result, test.Value1, test.Value2)
-		}
-	}
-}
-
-var eqvTests = []struct {
-	Value1 Value
-	Value2 Value
-	Result Value
-}{
-	{
-		Value1: TRUE,
-		Value2: FALSE,
+		A:      UNKNOWN,
+		B:      FALSE,
 		Result: FALSE,
+		Called: true,
 	},
 	{
-		Value1: TRUE,
-		Value2: UNKNOWN,
+		A:      UNKNOWN,
+		B:      TRUE,
 		Result: UNKNOWN,
+		Called: true,
 	},
 	{
-		Value1: FALSE,
-		Value2: FALSE,
+		A:      TRUE,
+		B:      TRUE,
 		Result: TRUE,
+		Called: true,
 	},
 }
 
-func TestEqv(t *testing.T) {
-	for _, test := range eqvTests {
-		v := Eqv(test.Value1, test.Value2)
+func TestAndThen(t *testing.T) {
+	for _, test := range andThenTests {
+		called := false
+		v := AndThen(test.A, func() Value {
+			called = true
+			return test.B
+		})
 		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for \"%s eqv %s\"", v, test.Result, test.Value1, test.Value2)
+			t.Errorf("ternary = %s, want %s for %s and %s", v, test.Result, test.A, test.B)
+		}
+		if called != test.Called {
+			t.Errorf("called = %t, want %t for %s and %s", called, test.Called, test.A, test.B)
 		}
 	}
 }
 
-var allTests = []struct {
-	ValueList []Value
-	Result    Value
+var orElseTests = []struct {
+	A      Value
+	B      Value
+	Result Value
+	Called bool
 }{
 	{
-		ValueList: []Value{TRUE, TRUE, TRUE},
-		Result:    TRUE,
+		A:      TRUE,
+		B:      FALSE,
+		Result: TRUE,
+		Called: false,
 	},
 	{
-		ValueList: []Value{TRUE, UNKNOWN, TRUE},
-		Result:    UNKNOWN,
+		A:      UNKNOWN,
+		B:      TRUE,
+		Result: TRUE,
+		Called: true,
 	},
 	{
-		ValueList: []Value{TRUE, UNKNOWN, FALSE},
-		Result:    FALSE,
+		A:      UNKNOWN,
+		B:      FALSE,
+		Result: UNKNOWN,
+		Called: true,
 	},
 	{
-		ValueList: []Value{},
-		Result:    TRUE,
+		A:      FALSE,
+		B:      FALSE,
+		Result: FALSE,
+		Called: true,
 	},
 }
 
-func TestAll(t *testing.T) {
-	for _, test := range allTests {
-		v := All(test.ValueList)
+func TestOrElse(t *testing.T) {
+	for _, test := range orElseTests {
+		called := false
+		v := OrElse(test.A, func() Value {
+			called = true
+			return test.B
+		})
 		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for all \"%s\"", v, test.Result, test.ValueList)
+			t.Errorf("ternary = %s, want %s for %s or %s", v, test.Result, test.A, test.B)
+		}
+		if called != test.Called {
+			t.Errorf("called = %t, want %t for %s or %s", called, test.Called, test.A, test.B)
 		}
 	}
 }
 
-var anyTests = []struct {
-	ValueList []Value
-	Result    Value
+var packedTests = [][]Value{
+	{},
+	{TRUE},
+	{FALSE, UNKNOWN, TRUE},
+	{FALSE, UNKNOWN, TRUE, TRUE},
+	{TRUE, FALSE, UNKNOWN, FALSE, TRUE},
+	{UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN, UNKNOWN},
+}
+
+func TestMarshalPacked(t *testing.T) {
+	for _, values := range packedTests {
+		s := MarshalPacked(values)
+		v, err := UnmarshalPacked(s)
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(v, values) {
+			t.Errorf("values = %s, want %s for %q", v, values, s)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		values := make([]Value, r.Intn(100))
+		for j := range values {
+			values[j] = Value(r.Intn(3) - 1)
+		}
+		v, err := UnmarshalPacked(MarshalPacked(values))
+		if err != nil {
+			t.Errorf("unexpected error: %q", err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(v, values) {
+			t.Errorf("values = %s, want %s", v, values)
+		}
+	}
+}
+
+var unmarshalPackedTests = []struct {
+	String string
+	Result []Value
+	Err    string
 }{
 	{
-		ValueList: []Value{TRUE, UNKNOWN, FALSE},
-		Result:    TRUE,
+		String: "AAAAAAAAAAM=",
+		Result: nil,
+		Err:    "unpack 3 values from 0 bytes: length mismatch",
 	},
 	{
-		ValueList: []Value{FALSE, UNKNOWN, FALSE},
-		Result:    UNKNOWN,
+		String: "AAAAAAAAAAEC",
+		Result: []Value{TRUE},
 	},
 	{
-		ValueList: []Value{FALSE, FALSE, FALSE},
-		Result:    FALSE,
+		String: "AAAAAAAAAAED",
+		Result: nil,
+		Err:    "unpack value at 0: invalid code 3",
 	},
 	{
-		ValueList: []Value{},
-		Result:    FALSE,
+		String: "AAAAAAAAAAEG",
+		Result: nil,
+		Err:    "unpack 1 values: non-zero padding",
+	},
+	{
+		String: "AAAAAAAAAAECAA==",
+		Result: nil,
+		Err:    "unpack 1 values from 2 bytes: length mismatch",
+	},
+	{
+		String: "AAAA",
+		Result: nil,
+		Err:    "unpack values from \"AAAA\": missing length",
+	},
+	{
+		String: "!!!",
+		Result: nil,
+		Err:    "unpack values from \"!!!\": invalid base64 encoding",
 	},
 }
 
-func TestAny(t *testing.T) {
-	for _, test := range anyTests {
-		v := Any(test.ValueList)
-		if v != test.Result {
-			t.Errorf("ternary = %s, want %s for any \"%s\"", v, test.Result, test.ValueList)
+func TestUnmarshalPacked(t *testing.T) {
+	for _, test := range unmarshalPackedTests {
+		v, err := UnmarshalPacked(test.String)
+		if err != nil {
+			if len(test.Err) < 1 {
+				t.Errorf("unexpected error: %q", err.Error())
+			} else if err.Error() != test.Err {
+				t.Errorf("error = %q, want error %q for %q", err.Error(), test.Err, test.String)
+			}
+			continue
+		}
+		if 0 < len(test.Err) {
+			t.Errorf("no error, want error %q for %q", test.Err, test.String)
+			continue
+		}
+		if !reflect.DeepEqual(v, test.Result) {
+			t.Errorf("values = %s, want %s for %q", v, test.Result, test.String)
 		}
 	}
 }